@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/middleware"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"roles":   models.RolePermissions,
+	})
+}
+
+func UpdateUserRole(c *gin.Context) {
+	db := config.DB
+	adminID := c.GetUint("user_id")
+
+	targetUserID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role is required"})
+		return
+	}
+
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if !models.IsValidRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	if uint(targetUserID) == adminID && role != models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot remove your own admin role"})
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, targetUserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if err := db.Model(&user).Update("role", role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "Role updated successfully",
+		"user_id":     user.ID,
+		"role":        role,
+		"permissions": models.RolePermissions[role],
+	})
+}
+
+func UpdateUserBanStatus(c *gin.Context) {
+	db := config.DB
+	adminID := c.GetUint("user_id")
+
+	targetUserID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if uint(targetUserID) == adminID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot ban yourself"})
+		return
+	}
+
+	var req struct {
+		IsBanned *bool `json:"is_banned"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.IsBanned == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "is_banned is required"})
+		return
+	}
+
+	var user models.User
+	if err := db.First(&user, targetUserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if user.Role == models.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admins cannot be banned"})
+		return
+	}
+
+	if err := db.Model(&user).Update("is_active", !*req.IsBanned).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+		return
+	}
+	middleware.ForgetUserStatus(user.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"user_id":   user.ID,
+		"is_banned": *req.IsBanned,
+	})
+}
+
+func ModerateRoom(c *gin.Context) {
+	db := config.DB
+
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var req struct {
+		IsHidden *bool  `json:"is_hidden"`
+		Reason   string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.IsHidden == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "is_hidden is required"})
+		return
+	}
+
+	var room models.Room
+	if err := db.First(&room, roomID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	updates := map[string]interface{}{
+		"is_hidden":     *req.IsHidden,
+		"hidden_at":     nil,
+		"hidden_reason": "",
+	}
+	if *req.IsHidden {
+		now := time.Now()
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			reason = "Hidden by moderator"
+		}
+		updates["hidden_at"] = &now
+		updates["hidden_reason"] = reason
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&room).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RoomReport{}).
+			Where("room_id = ? AND status = ?", room.ID, "pending").
+			Update("status", "reviewed").Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate room"})
+		return
+	}
+
+	db.First(&room, roomID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"room":    room,
+	})
+}
+
+func ReconcileCounters(c *gin.Context) {
+	discrepancies, err := services.NewCounterReconciler(config.DB).Reconcile()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":         "Failed to reconcile counters",
+			"discrepancies": discrepancies,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"fixed":         len(discrepancies),
+		"discrepancies": discrepancies,
+	})
+}
@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxAnalyticsRangeDays = 366
+
+// AnalyticsPoint is one bucket of a creator analytics series.
+type AnalyticsPoint struct {
+	PeriodStart     string  `json:"period_start"`
+	Listens         int64   `json:"listens"`
+	UniqueListeners int64   `json:"unique_listeners"`
+	NewListeners    int64   `json:"new_listeners"`
+	AvgCompletion   float64 `json:"avg_completion"`
+	SkipRate        float64 `json:"skip_rate"`
+	Completions     int64   `json:"completions"`
+	Skips           int64   `json:"skips"`
+	Likes           int64   `json:"likes"`
+	Comments        int64   `json:"comments"`
+	Downloads       int64   `json:"downloads"`
+	NewFollowers    int64   `json:"new_followers"`
+}
+
+type analyticsRange struct {
+	Interval string
+	From     time.Time
+	To       time.Time
+}
+
+func parseAnalyticsRange(c *gin.Context) (*analyticsRange, error) {
+	interval := c.DefaultQuery("interval", models.AnalyticsPeriodDay)
+	if interval != models.AnalyticsPeriodDay && interval != models.AnalyticsPeriodWeek {
+		return nil, fmt.Errorf("interval must be day or week")
+	}
+
+	today := models.AnalyticsPeriodStart(models.AnalyticsPeriodDay, time.Now())
+	to := today
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("to must be a date (YYYY-MM-DD)")
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("from must be a date (YYYY-MM-DD)")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+	if to.Sub(from) > maxAnalyticsRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range must be at most %d days", maxAnalyticsRangeDays)
+	}
+
+	return &analyticsRange{
+		Interval: interval,
+		From:     models.AnalyticsPeriodStart(interval, from),
+		To:       to,
+	}, nil
+}
+
+// buckets lists every period start in the range so days without activity
+// still appear as zeros.
+func (r *analyticsRange) buckets() []time.Time {
+	var buckets []time.Time
+	for t := r.From; !t.After(r.To); {
+		buckets = append(buckets, t)
+		if r.Interval == models.AnalyticsPeriodWeek {
+			t = t.AddDate(0, 0, 7)
+		} else {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return buckets
+}
+
+func analyticsPoint(periodStart time.Time, stats models.AnalyticsStats, newFollowers int64) AnalyticsPoint {
+	point := AnalyticsPoint{
+		PeriodStart:     periodStart.Format("2006-01-02"),
+		Listens:         stats.Listens,
+		UniqueListeners: stats.UniqueListeners,
+		NewListeners:    stats.NewListeners,
+		Completions:     stats.Completions,
+		Skips:           stats.Skips,
+		Likes:           stats.Likes,
+		Comments:        stats.Comments,
+		Downloads:       stats.Downloads,
+		NewFollowers:    newFollowers,
+	}
+	if stats.CompletionCount > 0 {
+		point.AvgCompletion = stats.CompletionSum / float64(stats.CompletionCount)
+	}
+	if stats.Listens > 0 {
+		point.SkipRate = float64(stats.Skips) / float64(stats.Listens)
+	}
+	return point
+}
+
+// GetChannelAnalytics returns the signed-in creator's channel-wide series.
+func GetChannelAnalytics(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	r, err := parseAnalyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rows []models.ChannelStat
+	if err := config.DB.
+		Where("host_id = ? AND period = ? AND period_start BETWEEN ? AND ?", userID, r.Interval, r.From, r.To).
+		Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch analytics"})
+		return
+	}
+
+	byStart := map[string]models.ChannelStat{}
+	for _, row := range rows {
+		byStart[row.PeriodStart.Format("2006-01-02")] = row
+	}
+
+	series := make([]AnalyticsPoint, 0)
+	var totals models.AnalyticsStats
+	var totalFollowers int64
+	for _, start := range r.buckets() {
+		row := byStart[start.Format("2006-01-02")]
+		series = append(series, analyticsPoint(start, row.AnalyticsStats, row.NewFollowers))
+		addAnalyticsStats(&totals, row.AnalyticsStats)
+		totalFollowers += row.NewFollowers
+	}
+
+	respondAnalytics(c, r, fmt.Sprintf("channel-%d", userID), series, analyticsPoint(r.From, totals, totalFollowers))
+}
+
+// GetRoomAnalytics returns the series for one of the creator's rooms.
+func GetRoomAnalytics(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.Where("id = ? AND host_id = ?", roomID, userID).First(&room).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	r, err := parseAnalyticsRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rows []models.RoomStat
+	if err := config.DB.
+		Where("room_id = ? AND period = ? AND period_start BETWEEN ? AND ?", room.ID, r.Interval, r.From, r.To).
+		Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch analytics"})
+		return
+	}
+
+	byStart := map[string]models.RoomStat{}
+	for _, row := range rows {
+		byStart[row.PeriodStart.Format("2006-01-02")] = row
+	}
+
+	series := make([]AnalyticsPoint, 0)
+	var totals models.AnalyticsStats
+	for _, start := range r.buckets() {
+		row := byStart[start.Format("2006-01-02")]
+		series = append(series, analyticsPoint(start, row.AnalyticsStats, 0))
+		addAnalyticsStats(&totals, row.AnalyticsStats)
+	}
+
+	respondAnalytics(c, r, fmt.Sprintf("room-%d", room.ID), series, analyticsPoint(r.From, totals, 0))
+}
+
+const retentionHotspotLimit = 5
+
+// GetRoomRetention returns the room's audience retention curve and the
+// sections listeners replay or skip most.
+func GetRoomRetention(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.Where("id = ? AND host_id = ?", roomID, userID).First(&room).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	sessions, curve, err := services.NewRetentionService(config.DB).RetentionCurve(room.ID, room.Duration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch retention"})
+		return
+	}
+
+	replays := topRetentionPoints(curve, func(p services.RetentionPoint) int64 { return p.Replays })
+	skips := topRetentionPoints(curve, func(p services.RetentionPoint) int64 { return p.Skips })
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"room_id":         room.ID,
+		"duration":        room.Duration,
+		"bucket_seconds":  models.RetentionBucketSeconds,
+		"listens":         sessions,
+		"curve":           curve,
+		"replay_hotspots": replays,
+		"skip_hotspots":   skips,
+	})
+}
+
+// topRetentionPoints returns the buckets with the highest non-zero metric.
+func topRetentionPoints(curve []services.RetentionPoint, metric func(services.RetentionPoint) int64) []services.RetentionPoint {
+	top := make([]services.RetentionPoint, 0)
+	for _, p := range curve {
+		if metric(p) > 0 {
+			top = append(top, p)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool { return metric(top[i]) > metric(top[j]) })
+	if len(top) > retentionHotspotLimit {
+		top = top[:retentionHotspotLimit]
+	}
+	return top
+}
+
+// addAnalyticsStats sums bucket counters into the range totals. Unique
+// listeners can't be summed across buckets, so the total is left at zero.
+func addAnalyticsStats(totals *models.AnalyticsStats, stats models.AnalyticsStats) {
+	totals.Listens += stats.Listens
+	totals.NewListeners += stats.NewListeners
+	totals.CompletionSum += stats.CompletionSum
+	totals.CompletionCount += stats.CompletionCount
+	totals.Completions += stats.Completions
+	totals.Skips += stats.Skips
+	totals.Likes += stats.Likes
+	totals.Comments += stats.Comments
+	totals.Downloads += stats.Downloads
+}
+
+func respondAnalytics(c *gin.Context, r *analyticsRange, name string, series []AnalyticsPoint, totals AnalyticsPoint) {
+	if c.Query("format") == "csv" {
+		filename := fmt.Sprintf("%s-%s-%s_%s.csv", name, r.Interval, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+		writer := csv.NewWriter(c.Writer)
+		writer.Write([]string{
+			"period_start", "listens", "unique_listeners", "new_listeners", "avg_completion", "skip_rate",
+			"completions", "skips", "likes", "comments", "downloads", "new_followers",
+		})
+		for _, p := range series {
+			writer.Write([]string{
+				p.PeriodStart,
+				strconv.FormatInt(p.Listens, 10),
+				strconv.FormatInt(p.UniqueListeners, 10),
+				strconv.FormatInt(p.NewListeners, 10),
+				strconv.FormatFloat(p.AvgCompletion, 'f', 4, 64),
+				strconv.FormatFloat(p.SkipRate, 'f', 4, 64),
+				strconv.FormatInt(p.Completions, 10),
+				strconv.FormatInt(p.Skips, 10),
+				strconv.FormatInt(p.Likes, 10),
+				strconv.FormatInt(p.Comments, 10),
+				strconv.FormatInt(p.Downloads, 10),
+				strconv.FormatInt(p.NewFollowers, 10),
+			})
+		}
+		writer.Flush()
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"interval": r.Interval,
+		"from":     r.From.Format("2006-01-02"),
+		"to":       r.To.Format("2006-01-02"),
+		"series":   series,
+		"totals":   totals,
+	})
+}
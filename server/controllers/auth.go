@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"voxarena_server/config"
 	"voxarena_server/dto"
 	"voxarena_server/models"
@@ -71,6 +72,17 @@ func GoogleAuth(c *gin.Context) {
 		}
 	}
 
+	if user.Role != models.RoleAdmin && isBootstrapAdmin(user.Email) {
+		if err := config.DB.Model(&user).Update("role", models.RoleAdmin).Error; err == nil {
+			user.Role = models.RoleAdmin
+		}
+	}
+
+	if !user.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This account has been suspended"})
+		return
+	}
+
 	token, err := utils.GenerateJWT(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
@@ -90,6 +102,17 @@ func GoogleAuth(c *gin.Context) {
 	})
 }
 
+// isBootstrapAdmin reports whether email is listed in ADMIN_EMAILS, which is
+// how the first admin account gets its role before any admin can grant one.
+func isBootstrapAdmin(email string) bool {
+	for _, adminEmail := range strings.Split(config.GetEnv("ADMIN_EMAILS", ""), ",") {
+		if adminEmail = strings.TrimSpace(adminEmail); adminEmail != "" && strings.EqualFold(adminEmail, email) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetMe(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -55,13 +56,13 @@ func CreateComment(c *gin.Context) {
 	}
 
 	var room models.Room
-	if err := db.First(&room, roomID).Error; err != nil {
+	if err := db.First(&room, roomID).Error; err != nil || !room.IsVisibleTo(userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
 
+	var parentComment models.Comment
 	if req.ParentID != nil {
-		var parentComment models.Comment
 		if err := db.First(&parentComment, *req.ParentID).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found"})
 			return
@@ -80,7 +81,15 @@ func CreateComment(c *gin.Context) {
 		ReplyToUserID: req.ReplyToUserID,
 	}
 
-	if err := db.Create(&comment).Error; err != nil {
+	var mentions []models.Mention
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		var err error
+		mentions, err = models.SyncMentions(tx, models.MentionSourceComment, comment.ID, userID, comment.Content)
+		return err
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
@@ -88,6 +97,7 @@ func CreateComment(c *gin.Context) {
 	db.
 		Preload("User").
 		Preload("ReplyToUser").
+		Preload("Mentions").
 		First(&comment, comment.ID)
 
 	notificationService := services.NewNotificationService(db)
@@ -95,6 +105,25 @@ func CreateComment(c *gin.Context) {
 		if err := notificationService.NotifyNewComment(&room, &comment, comment.User); err != nil {
 			log.Printf("⚠️ Failed to send comment notification: %v", err)
 		}
+
+		skipMentionIDs := []uint{room.HostID}
+		if req.ParentID != nil && parentComment.UserID != room.HostID {
+			if err := notificationService.NotifyCommentReply(&parentComment, &comment, comment.User, &room); err != nil {
+				log.Printf("⚠️ Failed to send comment reply notification: %v", err)
+			}
+			skipMentionIDs = append(skipMentionIDs, parentComment.UserID)
+		}
+
+		if err := notificationService.NotifyMentions(comment.User, mentions, services.MentionTarget{
+			Where:         "a comment",
+			Message:       comment.Content,
+			ReferenceID:   room.ID,
+			ReferenceType: "room",
+			ImageURL:      room.ThumbnailURL,
+			ActionURL:     fmt.Sprintf("/rooms/%d", room.ID),
+		}, skipMentionIDs...); err != nil {
+			log.Printf("⚠️ Failed to send mention notifications: %v", err)
+		}
 	}()
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -127,9 +156,11 @@ func GetComments(c *gin.Context) {
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("comments")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var blockedBy []uint
 	if userID > 0 {
@@ -150,18 +181,17 @@ func GetComments(c *gin.Context) {
 	}
 
 	var comments []models.Comment
-	if err := query.
-		Preload("User").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&comments).Error; err != nil {
+	if err := pagination.Apply(query.Preload("User").Preload("Mentions")).Find(&comments).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch comments",
 		})
 		return
 	}
 
+	comments, nextCursor := finishPage(pagination, comments, func(comment models.Comment) []interface{} {
+		return []interface{}{comment.CreatedAt, comment.ID}
+	})
+
 	var likedCommentIDs []uint
 	if userID > 0 {
 		db.Model(&models.CommentLike{}).
@@ -220,6 +250,7 @@ func GetComments(c *gin.Context) {
 		for i := range allReplies {
 			db.Preload("User").
 				Preload("ReplyToUser").
+				Preload("Mentions").
 				First(&allReplies[i], allReplies[i].ID)
 		}
 
@@ -235,6 +266,7 @@ func GetComments(c *gin.Context) {
 				"content":       reply.Content,
 				"user":          reply.User,
 				"reply_to_user": reply.ReplyToUser,
+				"mentions":      reply.Mentions,
 				"parent_id":     reply.ParentID,
 				"created_at":    reply.CreatedAt,
 				"likes_count":   reply.LikesCount,
@@ -293,11 +325,12 @@ func GetComments(c *gin.Context) {
 	totalQuery.Count(&total)
 
 	c.JSON(http.StatusOK, gin.H{
-		"comments": response,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(comments) < int(total),
+		"comments":    response,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -325,9 +358,14 @@ func GetReplies(c *gin.Context) {
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c,
+		SortKey{Column: "reply_tree.created_at"},
+		SortKey{Column: "reply_tree.id"},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var blockedBy []uint
 	if userID > 0 {
@@ -369,17 +407,18 @@ func GetReplies(c *gin.Context) {
 	rawQuery += `
 		)
 		SELECT * FROM reply_tree
-		ORDER BY created_at ASC
-		LIMIT ? OFFSET ?
 	`
 
-	args = append(args, limit, offset)
+	pagination.Apply(db.Table("(?) AS reply_tree", db.Raw(rawQuery, args...))).Scan(&replies)
 
-	db.Raw(rawQuery, args...).Scan(&replies)
+	replies, nextCursor := finishPage(pagination, replies, func(reply models.Comment) []interface{} {
+		return []interface{}{reply.CreatedAt, reply.ID}
+	})
 
 	for i := range replies {
 		db.Preload("User").
 			Preload("ReplyToUser").
+			Preload("Mentions").
 			First(&replies[i], replies[i].ID)
 	}
 
@@ -441,11 +480,12 @@ func GetReplies(c *gin.Context) {
 	db.Raw(countQuery, countArgs...).Count(&total)
 
 	c.JSON(http.StatusOK, gin.H{
-		"replies":  response,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(replies) < int(total),
+		"replies":     response,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -579,19 +619,15 @@ func ToggleCommentLike(c *gin.Context) {
 	err = db.Where("comment_id = ? AND user_id = ?", commentID, userID).First(&like).Error
 
 	if err == gorm.ErrRecordNotFound {
-		like = models.CommentLike{
-			CommentID: uint(commentID),
-			UserID:    userID,
-		}
-		if err := db.Create(&like).Error; err != nil {
+		created, err := models.AddCommentLike(db, userID, uint(commentID))
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like comment"})
 			return
 		}
 
-		db.Model(&comment).Update("likes_count", gorm.Expr("likes_count + ?", 1))
 		db.First(&comment, commentID)
 
-		if comment.UserID != userID {
+		if created && comment.UserID != userID {
 			hostName := "a post"
 			if comment.Room.Host.FullName != "" {
 				hostName = comment.Room.Host.FullName + "'s audio"
@@ -647,10 +683,14 @@ func ToggleCommentLike(c *gin.Context) {
 			"message":     "Comment liked",
 		})
 	} else if err == nil {
-		db.Delete(&like)
-		db.Model(&comment).Update("likes_count", gorm.Expr("GREATEST(likes_count - ?, 0)", 1))
+		removed, err := models.RemoveCommentLike(db, userID, uint(commentID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike comment"})
+			return
+		}
+
 		db.First(&comment, commentID)
-		if comment.UserID != userID {
+		if removed && comment.UserID != userID {
 			db.Where("user_id = ? AND actor_id = ? AND type = ? AND message = ?",
 				comment.UserID, userID, models.NotificationTypeCommentLike, comment.Content).
 				Delete(&models.Notification{})
@@ -117,6 +117,19 @@ func CreateCommunityPost(c *gin.Context) {
 		return
 	}
 
+	if err := models.SyncCommunityPostHashtags(tx, post.ID, post.Content); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save hashtags"})
+		return
+	}
+
+	mentions, err := models.SyncMentions(tx, models.MentionSourceCommunityPost, post.ID, post.UserID, post.Content)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save mentions"})
+		return
+	}
+
 	form, _ := c.MultipartForm()
 	images := form.File["images"]
 
@@ -173,13 +186,19 @@ func CreateCommunityPost(c *gin.Context) {
 
 	tx.Commit()
 
-	config.DB.Preload("User").First(&post, post.ID)
+	config.DB.Preload("User").Preload("Mentions").First(&post, post.ID)
 
 	notifService := services.NewNotificationService(config.DB)
 	if err := notifService.NotifyNewCommunityPost(&post); err != nil {
 		fmt.Printf("Failed to send notifications for new post: %v\n", err)
 	}
 
+	var mentionImageURL string
+	if len(imageURLs) > 0 {
+		mentionImageURL = imageURLs[0]
+	}
+	notifyPostMentions(notifService, &post, mentions, mentionImageURL)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "Community post created successfully",
@@ -192,6 +211,7 @@ func CreateCommunityPost(c *gin.Context) {
 			"images":         imageURLs,
 			"likes_count":    post.LikesCount,
 			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
 			"created_at":     post.CreatedAt,
 			"user":           post.User,
 		},
@@ -214,24 +234,18 @@ func GetCommunityPosts(c *gin.Context) {
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("community_posts")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	db := config.DB
 	var posts []models.CommunityPost
 	var total int64
 
 	var blockedBy []uint
 	if viewerID > 0 {
-		var err error
 		blockedBy, err = GetUsersWhoHidMe(db, viewerID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden relations"})
@@ -246,16 +260,15 @@ func GetCommunityPosts(c *gin.Context) {
 
 	query.Count(&total)
 
-	if err := query.
-		Preload("User").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&posts).Error; err != nil {
+	if err := pagination.Apply(query.Preload("User").Preload("Mentions")).Find(&posts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
 	}
 
+	posts, nextCursor := finishPage(pagination, posts, func(p models.CommunityPost) []interface{} {
+		return []interface{}{p.CreatedAt, p.ID}
+	})
+
 	var likedPostIDs []uint
 	if viewerID > 0 {
 		db.Model(&models.CommunityPostLike{}).
@@ -285,6 +298,7 @@ func GetCommunityPosts(c *gin.Context) {
 			"images":         images,
 			"likes_count":    post.LikesCount,
 			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
 			"is_liked":       likedMap[post.ID],
 			"created_at":     post.CreatedAt,
 			"updated_at":     post.UpdatedAt,
@@ -294,12 +308,13 @@ func GetCommunityPosts(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"posts":    postsWithImages,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(posts) < int(total),
+		"success":     true,
+		"posts":       postsWithImages,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -322,7 +337,7 @@ func GetCommunityPostByID(c *gin.Context) {
 	}
 
 	var post models.CommunityPost
-	if err := config.DB.Preload("User").First(&post, postID).Error; err != nil {
+	if err := config.DB.Preload("User").Preload("Mentions").First(&post, postID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		return
 	}
@@ -363,6 +378,7 @@ func GetCommunityPostByID(c *gin.Context) {
 			"images":         images,
 			"likes_count":    post.LikesCount,
 			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
 			"is_liked":       isLiked,
 			"created_at":     post.CreatedAt,
 			"updated_at":     post.UpdatedAt,
@@ -386,17 +402,12 @@ func GetUserCommunityPosts(c *gin.Context) {
 		currentUserID = uint(v)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("community_posts")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	db := config.DB
 	var posts []models.CommunityPost
 	var total int64
@@ -406,12 +417,13 @@ func GetUserCommunityPosts(c *gin.Context) {
 		err := db.Where("user_id = ? AND hidden_user_id = ?", targetUserID, currentUserID).First(&hidden).Error
 		if err == nil {
 			c.JSON(http.StatusOK, gin.H{
-				"success":  true,
-				"posts":    []models.CommunityPost{},
-				"page":     page,
-				"limit":    limit,
-				"total":    0,
-				"has_more": false,
+				"success":     true,
+				"posts":       []models.CommunityPost{},
+				"page":        pagination.Page,
+				"limit":       pagination.Limit,
+				"total":       0,
+				"has_more":    false,
+				"next_cursor": nil,
 			})
 			return
 		}
@@ -420,16 +432,15 @@ func GetUserCommunityPosts(c *gin.Context) {
 	query := db.Model(&models.CommunityPost{}).Where("user_id = ?", targetUserID)
 	query.Count(&total)
 
-	if err := query.
-		Preload("User").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&posts).Error; err != nil {
+	if err := pagination.Apply(query.Preload("User").Preload("Mentions")).Find(&posts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
 	}
 
+	posts, nextCursor := finishPage(pagination, posts, func(p models.CommunityPost) []interface{} {
+		return []interface{}{p.CreatedAt, p.ID}
+	})
+
 	var likedPostIDs []uint
 	if currentUserID > 0 {
 		db.Model(&models.CommunityPostLike{}).
@@ -459,6 +470,7 @@ func GetUserCommunityPosts(c *gin.Context) {
 			"images":         images,
 			"likes_count":    post.LikesCount,
 			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
 			"is_liked":       likedMap[post.ID],
 			"created_at":     post.CreatedAt,
 			"updated_at":     post.UpdatedAt,
@@ -468,12 +480,13 @@ func GetUserCommunityPosts(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"posts":    postsWithImages,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(posts) < int(total),
+		"success":     true,
+		"posts":       postsWithImages,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -703,6 +716,19 @@ func UpdateCommunityPost(c *gin.Context) {
 		return
 	}
 
+	if err := models.SyncCommunityPostHashtags(tx, post.ID, post.Content); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save hashtags"})
+		return
+	}
+
+	mentions, err := models.SyncMentions(tx, models.MentionSourceCommunityPost, post.ID, post.UserID, post.Content)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save mentions"})
+		return
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit changes"})
 		return
@@ -713,7 +739,13 @@ func UpdateCommunityPost(c *gin.Context) {
 		Order("position ASC").
 		Find(&images)
 
-	config.DB.Preload("User").First(&post, post.ID)
+	config.DB.Preload("User").Preload("Mentions").First(&post, post.ID)
+
+	var mentionImageURL string
+	if len(images) > 0 {
+		mentionImageURL = images[0].ImageURL
+	}
+	notifyPostMentions(services.NewNotificationService(config.DB), &post, mentions, mentionImageURL)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -727,12 +759,34 @@ func UpdateCommunityPost(c *gin.Context) {
 			"images":         images,
 			"likes_count":    post.LikesCount,
 			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
 			"updated_at":     post.UpdatedAt,
 			"user":           post.User,
 		},
 	})
 }
 
+// notifyPostMentions notifies users newly mentioned in a post, in the
+// background.
+func notifyPostMentions(notifService *services.NotificationService, post *models.CommunityPost, mentions []models.Mention, imageURL string) {
+	if len(mentions) == 0 {
+		return
+	}
+	post = &models.CommunityPost{ID: post.ID, UserID: post.UserID, User: post.User, Content: post.Content}
+	go func() {
+		if err := notifService.NotifyMentions(post.User, mentions, services.MentionTarget{
+			Where:         "a post",
+			Message:       post.Content,
+			ReferenceID:   post.ID,
+			ReferenceType: "post",
+			ImageURL:      imageURL,
+			ActionURL:     fmt.Sprintf("/profile/%d?postId=%d", post.UserID, post.ID),
+		}); err != nil {
+			log.Printf("⚠️ Failed to send mention notifications: %v", err)
+		}
+	}()
+}
+
 func DeleteCommunityPost(c *gin.Context) {
 	userIDValue, exists := c.Get("user_id")
 	if !exists {
@@ -794,10 +848,46 @@ func DeleteCommunityPost(c *gin.Context) {
 }
 
 func ToggleCommunityPostLike(c *gin.Context) {
+	userID, post, ok := parseCommunityPostLikeRequest(c)
+	if !ok {
+		return
+	}
+
+	var like models.CommunityPostLike
+	err := config.DB.Where("community_post_id = ? AND user_id = ?", post.ID, userID).First(&like).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	setCommunityPostLike(c, userID, post.ID, err == gorm.ErrRecordNotFound)
+}
+
+func LikeCommunityPost(c *gin.Context) {
+	userID, post, ok := parseCommunityPostLikeRequest(c)
+	if !ok {
+		return
+	}
+
+	setCommunityPostLike(c, userID, post.ID, true)
+}
+
+func UnlikeCommunityPost(c *gin.Context) {
+	userID, post, ok := parseCommunityPostLikeRequest(c)
+	if !ok {
+		return
+	}
+
+	setCommunityPostLike(c, userID, post.ID, false)
+}
+
+func parseCommunityPostLikeRequest(c *gin.Context) (uint, models.CommunityPost, bool) {
+	var post models.CommunityPost
+
 	userIDValue, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
+		return 0, post, false
 	}
 
 	var userID uint
@@ -812,15 +902,14 @@ func ToggleCommunityPostLike(c *gin.Context) {
 		userID = uint(v)
 	default:
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
-		return
+		return 0, post, false
 	}
 
 	postID := c.Param("id")
 
-	var post models.CommunityPost
 	if err := config.DB.First(&post, postID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		return
+		return 0, post, false
 	}
 
 	var hidden models.HiddenUser
@@ -830,67 +919,43 @@ func ToggleCommunityPostLike(c *gin.Context) {
 			"error":         "You cannot interact with this content",
 			"is_restricted": true,
 		})
-		return
+		return 0, post, false
 	}
 
-	tx := config.DB.Begin()
-
-	var like models.CommunityPostLike
-	err = tx.Where("community_post_id = ? AND user_id = ?", postID, userID).First(&like).Error
+	return userID, post, true
+}
 
-	if err == gorm.ErrRecordNotFound {
-		like = models.CommunityPostLike{
-			CommunityPostID: post.ID,
-			UserID:          userID,
-		}
+func setCommunityPostLike(c *gin.Context, userID, postID uint, liked bool) {
+	var err error
+	if liked {
+		_, err = models.AddCommunityPostLike(config.DB, userID, postID)
+	} else {
+		_, err = models.RemoveCommunityPostLike(config.DB, userID, postID)
+	}
 
-		if err := tx.Create(&like).Error; err != nil {
-			tx.Rollback()
+	if err != nil {
+		if liked {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like post"})
-			return
-		}
-
-		if err := tx.Model(&post).Update("likes_count", gorm.Expr("likes_count + ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update likes count"})
-			return
-		}
-
-		tx.Commit()
-		config.DB.First(&post, postID)
-
-		c.JSON(http.StatusOK, gin.H{
-			"success":     true,
-			"liked":       true,
-			"likes_count": post.LikesCount,
-			"message":     "Post liked successfully",
-		})
-	} else if err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-	} else {
-		if err := tx.Delete(&like).Error; err != nil {
-			tx.Rollback()
+		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike post"})
-			return
-		}
-
-		if err := tx.Model(&post).Update("likes_count", gorm.Expr("likes_count - ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update likes count"})
-			return
 		}
+		return
+	}
 
-		tx.Commit()
-		config.DB.First(&post, postID)
+	var post models.CommunityPost
+	config.DB.Select("id", "likes_count").First(&post, postID)
 
-		c.JSON(http.StatusOK, gin.H{
-			"success":     true,
-			"liked":       false,
-			"likes_count": post.LikesCount,
-			"message":     "Post unliked successfully",
-		})
+	message := "Post unliked successfully"
+	if liked {
+		message = "Post liked successfully"
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"liked":       liked,
+		"likes_count": post.LikesCount,
+		"message":     message,
+	})
 }
 
 func CheckCommunityPostLikeStatus(c *gin.Context) {
@@ -1029,34 +1094,62 @@ func CreateCommunityPostComment(c *gin.Context) {
 		LikesCount:      0,
 	}
 
-	if err := db.Create(&comment).Error; err != nil {
+	var mentions []models.Mention
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+
+		var err error
+		mentions, err = models.SyncMentions(tx, models.MentionSourceCommunityComment, comment.ID, userID, comment.Content)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.CommunityPost{}).
+			Where("id = ?", post.ID).
+			Update("comments_count", gorm.Expr("comments_count + 1")).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
 
-	db.Model(&post).
-		Update("comments_count", gorm.Expr("comments_count + 1"))
-
 	db.Preload("User").
 		Preload("ReplyToUser").
+		Preload("Mentions").
 		First(&comment, comment.ID)
 
 	// Send notifications
 	notificationService := services.NewNotificationService(db)
 	go func() {
-		// If this is a reply/mention, notify the mentioned user
+		var skipMentionIDs []uint
+
+		// If this is a reply, notify the parent comment's author
 		if body.ParentID != nil && body.ReplyToUserID != nil {
 			var parentComment models.CommunityPostComment
 			if err := db.First(&parentComment, *body.ParentID).Error; err == nil {
 				if err := notificationService.NotifyCommunityPostCommentReply(&parentComment, &comment, comment.User, &post); err != nil {
 					log.Printf("⚠️ Failed to send community comment reply notification: %v", err)
 				}
+				skipMentionIDs = append(skipMentionIDs, parentComment.UserID)
 			}
 		} else {
 			// Regular comment - notify post owner
 			if err := notificationService.NotifyNewCommunityPostComment(&post, &comment, comment.User); err != nil {
 				log.Printf("⚠️ Failed to send community comment notification: %v", err)
 			}
+			skipMentionIDs = append(skipMentionIDs, post.UserID)
+		}
+
+		if err := notificationService.NotifyMentions(comment.User, mentions, services.MentionTarget{
+			Where:         "a comment",
+			Message:       comment.Content,
+			ReferenceID:   post.ID,
+			ReferenceType: "post",
+			ActionURL:     fmt.Sprintf("/profile/%d?postId=%d", post.UserID, post.ID),
+		}, skipMentionIDs...); err != nil {
+			log.Printf("⚠️ Failed to send mention notifications: %v", err)
 		}
 	}()
 
@@ -1085,20 +1178,15 @@ func GetCommunityPostComments(c *gin.Context) {
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("community_post_comments")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	offset := (page - 1) * limit
 
 	db := config.DB
 
 	var blockedBy []uint
-	var err error
 	if userID > 0 {
 		blockedBy, err = GetUsersWhoHidMe(db, userID)
 		if err != nil {
@@ -1113,16 +1201,15 @@ func GetCommunityPostComments(c *gin.Context) {
 	}
 
 	var comments []models.CommunityPostComment
-	if err := query.
-		Preload("User").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&comments).Error; err != nil {
+	if err := pagination.Apply(query.Preload("User").Preload("Mentions")).Find(&comments).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
 	}
 
+	comments, nextCursor := finishPage(pagination, comments, func(comment models.CommunityPostComment) []interface{} {
+		return []interface{}{comment.CreatedAt, comment.ID}
+	})
+
 	var likedCommentIDs []uint
 	if userID > 0 {
 		db.Model(&models.CommunityCommentLike{}).
@@ -1180,6 +1267,7 @@ func GetCommunityPostComments(c *gin.Context) {
 		for i := range allReplies {
 			db.Preload("User").
 				Preload("ReplyToUser").
+				Preload("Mentions").
 				First(&allReplies[i], allReplies[i].ID)
 		}
 
@@ -1195,6 +1283,7 @@ func GetCommunityPostComments(c *gin.Context) {
 				"content":       reply.Content,
 				"user":          reply.User,
 				"reply_to_user": reply.ReplyToUser,
+				"mentions":      reply.Mentions,
 				"parent_id":     reply.ParentID,
 				"created_at":    reply.CreatedAt,
 				"likes_count":   reply.LikesCount,
@@ -1252,12 +1341,13 @@ func GetCommunityPostComments(c *gin.Context) {
 	totalQuery.Count(&total)
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"comments": response,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(comments) < int(total),
+		"success":     true,
+		"comments":    response,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -1326,31 +1416,41 @@ func DeleteCommunityPostComment(c *gin.Context) {
 		return
 	}
 
-	var replyIDs []uint
-	db.Model(&models.CommunityPostComment{}).
-		Where("parent_id = ?", comment.ID).
-		Pluck("id", &replyIDs)
-
-	if len(replyIDs) > 0 {
-		db.Where("comment_id IN ?", replyIDs).
-			Delete(&models.CommunityCommentLike{})
-	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var threadIDs []uint
+		if err := tx.Raw(`
+			WITH RECURSIVE thread AS (
+				SELECT id FROM community_post_comments
+				WHERE id = ? AND deleted_at IS NULL
+				UNION ALL
+				SELECT c.id FROM community_post_comments c
+				INNER JOIN thread t ON c.parent_id = t.id
+				WHERE c.deleted_at IS NULL
+			)
+			SELECT id FROM thread
+		`, comment.ID).Scan(&threadIDs).Error; err != nil {
+			return err
+		}
 
-	db.Where("parent_id = ?", comment.ID).
-		Delete(&models.CommunityPostComment{})
+		if err := tx.Where("comment_id IN ?", threadIDs).
+			Delete(&models.CommunityCommentLike{}).Error; err != nil {
+			return err
+		}
 
-	db.Where("comment_id = ?", comment.ID).
-		Delete(&models.CommunityCommentLike{})
+		result := tx.Where("id IN ?", threadIDs).Delete(&models.CommunityPostComment{})
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if err := db.Delete(&comment).Error; err != nil {
+		return tx.Model(&models.CommunityPost{}).
+			Where("id = ?", comment.CommunityPostID).
+			Update("comments_count", gorm.Expr("GREATEST(comments_count - ?, 0)", result.RowsAffected)).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
 		return
 	}
 
-	db.Model(&models.CommunityPost{}).
-		Where("id = ?", comment.CommunityPostID).
-		Update("comments_count", gorm.Expr("comments_count - 1"))
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Comment deleted successfully",
@@ -1398,64 +1498,42 @@ func ToggleCommunityCommentLike(c *gin.Context) {
 		return
 	}
 
-	tx := config.DB.Begin()
-
 	var like models.CommunityCommentLike
-	err = tx.Where("comment_id = ? AND user_id = ?", commentID, userID).First(&like).Error
+	err = config.DB.Where("comment_id = ? AND user_id = ?", comment.ID, userID).First(&like).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
 
-	if err == gorm.ErrRecordNotFound {
-		like = models.CommunityCommentLike{
-			CommentID: comment.ID,
-			UserID:    userID,
-		}
+	liked := err == gorm.ErrRecordNotFound
+	if liked {
+		_, err = models.AddCommunityCommentLike(config.DB, userID, comment.ID)
+	} else {
+		_, err = models.RemoveCommunityCommentLike(config.DB, userID, comment.ID)
+	}
 
-		if err := tx.Create(&like).Error; err != nil {
-			tx.Rollback()
+	if err != nil {
+		if liked {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like comment"})
-			return
-		}
-
-		if err := tx.Model(&comment).Update("likes_count", gorm.Expr("likes_count + ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update likes count"})
-			return
-		}
-
-		tx.Commit()
-		config.DB.First(&comment, commentID)
-
-		c.JSON(http.StatusOK, gin.H{
-			"success":     true,
-			"liked":       true,
-			"likes_count": comment.LikesCount,
-			"message":     "Comment liked successfully",
-		})
-	} else if err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-	} else {
-		if err := tx.Delete(&like).Error; err != nil {
-			tx.Rollback()
+		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike comment"})
-			return
-		}
-
-		if err := tx.Model(&comment).Update("likes_count", gorm.Expr("likes_count - ?", 1)).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update likes count"})
-			return
 		}
+		return
+	}
 
-		tx.Commit()
-		config.DB.First(&comment, commentID)
+	config.DB.First(&comment, comment.ID)
 
-		c.JSON(http.StatusOK, gin.H{
-			"success":     true,
-			"liked":       false,
-			"likes_count": comment.LikesCount,
-			"message":     "Comment unliked successfully",
-		})
+	message := "Comment unliked successfully"
+	if liked {
+		message = "Comment liked successfully"
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"liked":       liked,
+		"likes_count": comment.LikesCount,
+		"message":     message,
+	})
 }
 
 func CheckCommunityCommentLikeStatus(c *gin.Context) {
@@ -1511,9 +1589,14 @@ func GetCommunityCommentReplies(c *gin.Context) {
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c,
+		SortKey{Column: "reply_tree.created_at"},
+		SortKey{Column: "reply_tree.id"},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var blockedBy []uint
 	if userID > 0 {
@@ -1555,17 +1638,18 @@ func GetCommunityCommentReplies(c *gin.Context) {
 	rawQuery += `
         )
         SELECT * FROM reply_tree
-        ORDER BY created_at ASC
-        LIMIT ? OFFSET ?
     `
 
-	args = append(args, limit, offset)
+	pagination.Apply(db.Table("(?) AS reply_tree", db.Raw(rawQuery, args...))).Scan(&replies)
 
-	db.Raw(rawQuery, args...).Scan(&replies)
+	replies, nextCursor := finishPage(pagination, replies, func(reply models.CommunityPostComment) []interface{} {
+		return []interface{}{reply.CreatedAt, reply.ID}
+	})
 
 	for i := range replies {
 		db.Preload("User").
 			Preload("ReplyToUser").
+			Preload("Mentions").
 			First(&replies[i], replies[i].ID)
 	}
 
@@ -1627,11 +1711,12 @@ func GetCommunityCommentReplies(c *gin.Context) {
 	db.Raw(countQuery, countArgs...).Count(&total)
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"replies":  response,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(replies) < int(total),
+		"success":     true,
+		"replies":     response,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
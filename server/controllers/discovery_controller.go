@@ -9,6 +9,30 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	DiscoverySortTrending = "trending"
+	DiscoverySortNew      = "new"
+	DiscoverySortTopWeek  = "top_week"
+	DiscoverySortTopAll   = "top_all"
+)
+
+var discoverySortKeys = map[string][]SortKey{
+	DiscoverySortTrending: {
+		{Column: "rooms.trending_score", Desc: true},
+		{Column: "rooms.id", Desc: true},
+	},
+	DiscoverySortNew: createdAtKeys("rooms"),
+	DiscoverySortTopWeek: {
+		{Column: "rooms.weekly_listens", Desc: true},
+		{Column: "rooms.id", Desc: true},
+	},
+	DiscoverySortTopAll: {
+		{Column: "rooms.total_listens", Desc: true},
+		{Column: "rooms.created_at", Desc: true},
+		{Column: "rooms.id", Desc: true},
+	},
+}
+
 func GetDiscoveryFeed(c *gin.Context) {
 	userIDValue, _ := c.Get("user_id")
 
@@ -24,18 +48,26 @@ func GetDiscoveryFeed(c *gin.Context) {
 		viewerID = uint(v)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	topic := c.Query("topic")
 
-	if page < 1 {
-		page = 1
-	}
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	sort := c.DefaultQuery("sort", DiscoverySortTrending)
+	keys, ok := discoverySortKeys[sort]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "sort must be one of trending, new, top_week, top_all",
+		})
+		return
 	}
 
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, keys...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	db := config.DB
 	var rooms []models.Room
@@ -57,7 +89,8 @@ func GetDiscoveryFeed(c *gin.Context) {
 		Preload("Host").
 		Where("is_private = ?", false).
 		Where("is_live = ?", false).
-		Where("is_hidden = ?", false)
+		Where("is_hidden = ?", false).
+		Where("status = ?", models.RoomStatusPublished)
 
 	if len(hiddenByUserIDs) > 0 {
 		query = query.Where("host_id NOT IN ?", hiddenByUserIDs)
@@ -67,11 +100,7 @@ func GetDiscoveryFeed(c *gin.Context) {
 		query = query.Where("topic = ?", topic)
 	}
 
-	if err := query.
-		Order("total_listens DESC, created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&rooms).Error; err != nil {
+	if err := pagination.Apply(query).Find(&rooms).Error; err != nil {
 
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -80,33 +109,88 @@ func GetDiscoveryFeed(c *gin.Context) {
 		return
 	}
 
-	hasMore := len(rooms) == limit
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		switch sort {
+		case DiscoverySortNew:
+			return []interface{}{r.CreatedAt, r.ID}
+		case DiscoverySortTopWeek:
+			return []interface{}{r.WeeklyListens, r.ID}
+		case DiscoverySortTopAll:
+			return []interface{}{r.TotalListens, r.CreatedAt, r.ID}
+		default:
+			return []interface{}{r.TrendingScore, r.ID}
+		}
+	})
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"rooms":    rooms,
-		"page":     page,
-		"limit":    limit,
-		"has_more": hasMore,
+		"success":     true,
+		"sort":        sort,
+		"rooms":       rooms,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
-func GetTopics(c *gin.Context) {
-	topics := []string{
-		"All",
-		"Technology",
-		"Business",
-		"Gaming",
-		"Music",
-		"Education",
-		"Health",
-		"Entertainment",
-		"Sports",
-		"News",
+// GetTrendingTopics ranks topics by the combined trending score of their
+// public rooms and includes each topic's top rooms.
+func GetTrendingTopics(c *gin.Context) {
+	db := config.DB
+
+	limit := 10
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+	roomsPerTopic := 3
+	if l, err := strconv.Atoi(c.Query("rooms_per_topic")); err == nil && l >= 0 && l <= 10 {
+		roomsPerTopic = l
+	}
+
+	var topics []struct {
+		Topic         string  `json:"topic"`
+		TrendingScore float64 `json:"trending_score"`
+		WeeklyListens int     `json:"weekly_listens"`
+		RoomCount     int     `json:"room_count"`
+	}
+	if err := db.Model(&models.Room{}).
+		Select("topic, SUM(trending_score) AS trending_score, SUM(weekly_listens) AS weekly_listens, COUNT(*) AS room_count").
+		Where("is_private = ? AND is_hidden = ? AND is_live = ? AND status = ?", false, false, false, models.RoomStatusPublished).
+		Where("trending_score > 0").
+		Group("topic").
+		Order("trending_score DESC").
+		Limit(limit).
+		Scan(&topics).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to load trending topics",
+		})
+		return
+	}
+
+	results := make([]gin.H, 0, len(topics))
+	for _, topic := range topics {
+		rooms := []models.Room{}
+		if roomsPerTopic > 0 {
+			db.Preload("Host").
+				Where("topic = ?", topic.Topic).
+				Where("is_private = ? AND is_hidden = ? AND is_live = ? AND status = ?", false, false, false, models.RoomStatusPublished).
+				Order("trending_score DESC, id DESC").
+				Limit(roomsPerTopic).
+				Find(&rooms)
+		}
+
+		results = append(results, gin.H{
+			"topic":          topic.Topic,
+			"trending_score": topic.TrendingScore,
+			"weekly_listens": topic.WeeklyListens,
+			"room_count":     topic.RoomCount,
+			"rooms":          rooms,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"topics":  topics,
+		"topics":  results,
 	})
 }
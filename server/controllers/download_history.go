@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"net/http"
-	"strconv"
 	"time"
 	"voxarena_server/config"
 	"voxarena_server/models"
@@ -27,7 +26,7 @@ func TrackDownload(c *gin.Context) {
 	}
 
 	var room models.Room
-	if err := config.DB.First(&room, req.RoomID).Error; err != nil {
+	if err := config.DB.First(&room, req.RoomID).Error; err != nil || !room.IsVisibleTo(userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
@@ -74,9 +73,14 @@ func TrackDownload(c *gin.Context) {
 func GetUserDownloadHistory(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c,
+		SortKey{Column: "download_history.downloaded_at", Desc: true},
+		SortKey{Column: "download_history.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var downloads []models.DownloadHistory
 	var total int64
@@ -85,7 +89,6 @@ func GetUserDownloadHistory(c *gin.Context) {
 
 	var blockedBy []uint
 	if userID > 0 {
-		var err error
 		blockedBy, err = GetUsersWhoHidMe(db, userID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -119,7 +122,7 @@ func GetUserDownloadHistory(c *gin.Context) {
 		return
 	}
 
-	if err := query.
+	if err := pagination.Apply(query.
 		Preload(
 			"Room",
 			"(is_live = ? OR is_private = ? OR host_id = ?) AND is_hidden = ?",
@@ -128,10 +131,7 @@ func GetUserDownloadHistory(c *gin.Context) {
 			userID,
 			false,
 		).
-		Preload("Room.Host").
-		Order("download_history.downloaded_at DESC").
-		Limit(limit).
-		Offset(offset).
+		Preload("Room.Host")).
 		Find(&downloads).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch downloads",
@@ -139,6 +139,10 @@ func GetUserDownloadHistory(c *gin.Context) {
 		return
 	}
 
+	downloads, nextCursor := finishPage(pagination, downloads, func(d models.DownloadHistory) []interface{} {
+		return []interface{}{d.DownloadedAt, d.ID}
+	})
+
 	filteredDownloads := make([]models.DownloadHistory, 0)
 	for _, d := range downloads {
 		if d.Room.ID != 0 {
@@ -147,11 +151,12 @@ func GetUserDownloadHistory(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"downloads": filteredDownloads,
-		"total":     total,
-		"page":      page,
-		"limit":     limit,
-		"has_more":  offset+limit < int(total),
+		"downloads":   filteredDownloads,
+		"total":       total,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
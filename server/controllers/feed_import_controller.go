@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateFeedImport starts importing the episodes of an existing podcast,
+// either from a feed URL or an uploaded RSS file. The feed is parsed up
+// front so a bad feed fails the request; episodes are imported in the
+// background and progress is read from GetFeedImport.
+func CreateFeedImport(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	feedURL := strings.TrimSpace(c.PostForm("feed_url"))
+	topic := c.PostForm("topic")
+	isPrivate := c.PostForm("is_private") == "true"
+
+	if topic == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Topic is required"})
+		return
+	}
+
+	roomTopic, err := models.FindActiveTopic(config.DB, topic)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	var active int64
+	config.DB.Model(&models.FeedImport{}).
+		Where("user_id = ? AND status IN ?", userID, []string{models.FeedImportStatusPending, models.FeedImportStatusRunning}).
+		Count(&active)
+	if active > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "An import is already in progress"})
+		return
+	}
+
+	importService := services.NewFeedImportService(config.DB)
+
+	var data []byte
+	if fileHeader, err := c.FormFile("feed_file"); err == nil {
+		if fileHeader.Size > services.MaxImportFeedBytes {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Feed file must be less than 10MB"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read feed file"})
+			return
+		}
+		defer file.Close()
+
+		data, err = io.ReadAll(io.LimitReader(file, services.MaxImportFeedBytes))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read feed file"})
+			return
+		}
+		feedURL = ""
+	} else if feedURL != "" {
+		data, err = importService.FetchFeed(feedURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to fetch feed: " + err.Error()})
+			return
+		}
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "feed_url or feed_file is required"})
+		return
+	}
+
+	feed, err := services.ParsePodcastFeed(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feedImport, err := importService.CreateImport(userID, feedURL, *roomTopic, isPrivate, feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create import"})
+		return
+	}
+
+	importService.Start(feedImport.ID)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"import":  feedImport,
+	})
+}
+
+func GetFeedImports(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c, createdAtKeys("feed_imports")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imports := make([]models.FeedImport, 0)
+	query := config.DB.Model(&models.FeedImport{}).Where("feed_imports.user_id = ?", userID)
+	if err := pagination.Apply(query).Find(&imports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch imports"})
+		return
+	}
+
+	imports, nextCursor := finishPage(pagination, imports, func(i models.FeedImport) []interface{} {
+		return []interface{}{i.CreatedAt, i.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"imports":     imports,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+func findOwnFeedImport(c *gin.Context) (*models.FeedImport, bool) {
+	var feedImport models.FeedImport
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).
+		First(&feedImport).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+	return &feedImport, true
+}
+
+// GetFeedImport returns an import's progress with the per-episode report.
+// ?status= narrows the items to one status.
+func GetFeedImport(c *gin.Context) {
+	feedImport, ok := findOwnFeedImport(c)
+	if !ok {
+		return
+	}
+
+	items := make([]models.FeedImportItem, 0)
+	query := config.DB.Where("import_id = ?", feedImport.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("published_at DESC NULLS LAST, id DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"import":  feedImport,
+		"items":   items,
+	})
+}
+
+// RetryFeedImport queues the failed episodes of an import again.
+func RetryFeedImport(c *gin.Context) {
+	feedImport, ok := findOwnFeedImport(c)
+	if !ok {
+		return
+	}
+
+	retried, err := services.NewFeedImportService(config.DB).RetryFailed(feedImport.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry import"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"retried": retried,
+	})
+}
+
+// SkipFeedImportItem drops a pending or failed episode from the import.
+func SkipFeedImportItem(c *gin.Context) {
+	feedImport, ok := findOwnFeedImport(c)
+	if !ok {
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	result := config.DB.Model(&models.FeedImportItem{}).
+		Where("id = ? AND import_id = ? AND status IN ?", itemID, feedImport.ID,
+			[]string{models.FeedImportItemPending, models.FeedImportItemFailed}).
+		Updates(map[string]interface{}{"status": models.FeedImportItemSkipped, "error": "Skipped by user"})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to skip item"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No pending or failed item with that ID"})
+		return
+	}
+
+	services.RefreshFeedImportCounts(config.DB, feedImport.ID)
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -13,12 +13,46 @@ import (
 )
 
 func ToggleFollow(c *gin.Context) {
+	follower, targetUserID, ok := parseFollowRequest(c)
+	if !ok {
+		return
+	}
+
+	var follow models.Follow
+	err := config.DB.Where("follower_id = ? AND following_id = ?", follower.ID, targetUserID).First(&follow).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	setFollow(c, follower, targetUserID, err == gorm.ErrRecordNotFound)
+}
+
+func FollowUser(c *gin.Context) {
+	follower, targetUserID, ok := parseFollowRequest(c)
+	if !ok {
+		return
+	}
+
+	setFollow(c, follower, targetUserID, true)
+}
+
+func UnfollowUser(c *gin.Context) {
+	follower, targetUserID, ok := parseFollowRequest(c)
+	if !ok {
+		return
+	}
+
+	setFollow(c, follower, targetUserID, false)
+}
+
+func parseFollowRequest(c *gin.Context) (models.User, uint, bool) {
 	db := config.DB
 
 	userIDValue, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
+		return models.User{}, 0, false
 	}
 
 	var userID uint
@@ -33,90 +67,95 @@ func ToggleFollow(c *gin.Context) {
 		userID = uint(v)
 	default:
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID type"})
-		return
+		return models.User{}, 0, false
 	}
 
 	targetUserIDStr := c.Param("id")
 	if targetUserIDStr == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
-		return
+		return models.User{}, 0, false
 	}
 
 	targetUserID, err := strconv.Atoi(targetUserIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
+		return models.User{}, 0, false
 	}
 
 	if userID == uint(targetUserID) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot follow yourself"})
-		return
+		return models.User{}, 0, false
 	}
 
 	var targetUser models.User
 	if err := db.First(&targetUser, targetUserID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		return
+		return models.User{}, 0, false
 	}
 
 	var follower models.User
-	db.First(&follower, userID)
+	if err := db.First(&follower, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return models.User{}, 0, false
+	}
 
-	var follow models.Follow
-	err = db.Where("follower_id = ? AND following_id = ?", userID, targetUserID).First(&follow).Error
+	return follower, targetUser.ID, true
+}
 
-	if err == gorm.ErrRecordNotFound {
-		newFollow := models.Follow{
-			FollowerID:  userID,
-			FollowingID: uint(targetUserID),
-		}
+// setFollow moves the follow relation into the requested state. Notifications
+// are only sent or removed when the relation actually changed, so repeating
+// the request is harmless.
+func setFollow(c *gin.Context, follower models.User, targetUserID uint, follow bool) {
+	db := config.DB
+	userID := follower.ID
 
-		if err := db.Create(&newFollow).Error; err != nil {
+	if follow {
+		created, err := models.AddFollow(db, userID, targetUserID)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow user"})
 			return
 		}
 
-		db.Model(&models.User{}).Where("id = ?", userID).Update("following_count", gorm.Expr("following_count + ?", 1))
-		db.Model(&models.User{}).Where("id = ?", targetUserID).Update("followers_count", gorm.Expr("followers_count + ?", 1))
-
-		notification := models.Notification{
-			UserID:        uint(targetUserID),
-			ActorID:       userID,
-			Type:          models.NotificationTypeFollow,
-			Title:         "@" + follower.Username + " started following you",
-			Message:       follower.FullName,
-			ReferenceID:   &userID,
-			ReferenceType: "user",
-			ImageURL:      follower.ProfilePic,
-			ActionURL:     "/profile/" + strconv.Itoa(int(userID)),
-			IsRead:        false,
-		}
+		if created {
+			notification := models.Notification{
+				UserID:        targetUserID,
+				ActorID:       userID,
+				Type:          models.NotificationTypeFollow,
+				Title:         "@" + follower.Username + " started following you",
+				Message:       follower.FullName,
+				ReferenceID:   &userID,
+				ReferenceType: "user",
+				ImageURL:      follower.ProfilePic,
+				ActionURL:     "/profile/" + strconv.Itoa(int(userID)),
+				IsRead:        false,
+			}
 
-		if err := db.Create(&notification).Error; err == nil {
-			if websocket.GlobalHub != nil {
-				payload := map[string]interface{}{
-					"type": "notification",
-					"data": map[string]interface{}{
-						"id":             notification.ID,
-						"type":           notification.Type,
-						"title":          notification.Title,
-						"message":        notification.Message,
-						"image_url":      notification.ImageURL,
-						"action_url":     notification.ActionURL,
-						"reference_id":   notification.ReferenceID,
-						"reference_type": notification.ReferenceType,
-						"is_read":        notification.IsRead,
-						"created_at":     notification.CreatedAt,
-						"actor": map[string]interface{}{
-							"id":          follower.ID,
-							"full_name":   follower.FullName,
-							"username":    follower.Username,
-							"profile_pic": follower.ProfilePic,
+			if err := db.Create(&notification).Error; err == nil {
+				if websocket.GlobalHub != nil {
+					payload := map[string]interface{}{
+						"type": "notification",
+						"data": map[string]interface{}{
+							"id":             notification.ID,
+							"type":           notification.Type,
+							"title":          notification.Title,
+							"message":        notification.Message,
+							"image_url":      notification.ImageURL,
+							"action_url":     notification.ActionURL,
+							"reference_id":   notification.ReferenceID,
+							"reference_type": notification.ReferenceType,
+							"is_read":        notification.IsRead,
+							"created_at":     notification.CreatedAt,
+							"actor": map[string]interface{}{
+								"id":          follower.ID,
+								"full_name":   follower.FullName,
+								"username":    follower.Username,
+								"profile_pic": follower.ProfilePic,
+							},
 						},
-					},
+					}
+					websocket.GlobalHub.SendToUsers([]uint{targetUserID}, payload)
+					log.Printf("✓ Sent follow notification to user %d", targetUserID)
 				}
-				websocket.GlobalHub.SendToUsers([]uint{uint(targetUserID)}, payload)
-				log.Printf("✓ Sent follow notification to user %d", targetUserID)
 			}
 		}
 
@@ -124,19 +163,16 @@ func ToggleFollow(c *gin.Context) {
 			"message":      "User followed successfully",
 			"is_following": true,
 		})
-	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
-	} else {
-		// UNFOLLOW
-		if err := db.Delete(&follow).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
-			return
-		}
+	}
 
-		db.Model(&models.User{}).Where("id = ? AND following_count > 0", userID).Update("following_count", gorm.Expr("following_count - ?", 1))
-		db.Model(&models.User{}).Where("id = ? AND followers_count > 0", targetUserID).Update("followers_count", gorm.Expr("followers_count - ?", 1))
+	removed, err := models.RemoveFollow(db, userID, targetUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
+		return
+	}
 
+	if removed {
 		db.Where("user_id = ? AND actor_id = ? AND type = ?",
 			targetUserID, userID, models.NotificationTypeFollow).
 			Delete(&models.Notification{})
@@ -148,15 +184,15 @@ func ToggleFollow(c *gin.Context) {
 					"actor_id": userID,
 				},
 			}
-			websocket.GlobalHub.SendToUsers([]uint{uint(targetUserID)}, payload)
+			websocket.GlobalHub.SendToUsers([]uint{targetUserID}, payload)
 			log.Printf("✓ Sent remove_follow_notification to user %d", targetUserID)
 		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"message":      "User unfollowed successfully",
-			"is_following": false,
-		})
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "User unfollowed successfully",
+		"is_following": false,
+	})
 }
 
 func CheckFollowStatus(c *gin.Context) {
@@ -221,9 +257,11 @@ func GetFollowers(c *gin.Context) {
 		currentUserID = uint(v)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("follows")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if currentUserID > 0 {
 		var hidden models.HiddenUser
@@ -267,17 +305,17 @@ func GetFollowers(c *gin.Context) {
 		followersQuery = followersQuery.Where("follower_id NOT IN ?", hiddenByUserIDs)
 	}
 
-	if err := followersQuery.
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&followers).Error; err != nil {
+	if err := pagination.Apply(followersQuery).Find(&followers).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch followers",
 		})
 		return
 	}
 
+	followers, nextCursor := finishPage(pagination, followers, func(f models.Follow) []interface{} {
+		return []interface{}{f.CreatedAt, f.ID}
+	})
+
 	users := make([]map[string]interface{}, len(followers))
 	for i, follow := range followers {
 		users[i] = map[string]interface{}{
@@ -291,11 +329,12 @@ func GetFollowers(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"followers": users,
-		"page":      page,
-		"limit":     limit,
-		"total":     total,
-		"has_more":  offset+len(users) < int(total),
+		"followers":   users,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -321,9 +360,11 @@ func GetFollowing(c *gin.Context) {
 		currentUserID = uint(v)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("follows")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if currentUserID > 0 {
 		var hidden models.HiddenUser
@@ -367,17 +408,17 @@ func GetFollowing(c *gin.Context) {
 		followingQuery = followingQuery.Where("following_id NOT IN ?", hiddenByUserIDs)
 	}
 
-	if err := followingQuery.
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&following).Error; err != nil {
+	if err := pagination.Apply(followingQuery).Find(&following).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch following",
 		})
 		return
 	}
 
+	following, nextCursor := finishPage(pagination, following, func(f models.Follow) []interface{} {
+		return []interface{}{f.CreatedAt, f.ID}
+	})
+
 	users := make([]map[string]interface{}, len(following))
 	for i, follow := range following {
 		users[i] = map[string]interface{}{
@@ -391,11 +432,12 @@ func GetFollowing(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"following": users,
-		"page":      page,
-		"limit":     limit,
-		"total":     total,
-		"has_more":  offset+len(users) < int(total),
+		"following":   users,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -423,9 +465,11 @@ func GetFollowingRooms(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("rooms")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var follows []models.Follow
 	if err := db.
@@ -439,9 +483,10 @@ func GetFollowingRooms(c *gin.Context) {
 
 	if len(follows) == 0 {
 		c.JSON(http.StatusOK, gin.H{
-			"rooms":    []models.Room{},
-			"total":    0,
-			"has_more": false,
+			"rooms":       []models.Room{},
+			"total":       0,
+			"has_more":    false,
+			"next_cursor": nil,
 		})
 		return
 	}
@@ -477,9 +522,10 @@ func GetFollowingRooms(c *gin.Context) {
 
 	if len(followingIDs) == 0 {
 		c.JSON(http.StatusOK, gin.H{
-			"rooms":    []models.Room{},
-			"total":    0,
-			"has_more": false,
+			"rooms":       []models.Room{},
+			"total":       0,
+			"has_more":    false,
+			"next_cursor": nil,
 		})
 		return
 	}
@@ -487,30 +533,31 @@ func GetFollowingRooms(c *gin.Context) {
 	query := db.Model(&models.Room{}).
 		Where("host_id IN ?", followingIDs).
 		Where("is_private = ? OR host_id = ?", false, userID).
-		Where("is_hidden = ?", false)
+		Where("is_hidden = ?", false).
+		Where("status = ?", models.RoomStatusPublished)
 
 	var total int64
 	query.Count(&total)
 
 	var rooms []models.Room
-	if err := query.
-		Preload("Host").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&rooms).Error; err != nil {
+	if err := pagination.Apply(query.Preload("Host")).Find(&rooms).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch rooms",
 		})
 		return
 	}
 
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
 	c.JSON(http.StatusOK, gin.H{
-		"rooms":    rooms,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(rooms) < int(total),
+		"rooms":       rooms,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -544,34 +591,17 @@ func RemoveFollower(c *gin.Context) {
 		return
 	}
 
-	var follow models.Follow
-	err = db.Where(
-		"follower_id = ? AND following_id = ?",
-		targetUserID,
-		userID,
-	).First(&follow).Error
-
-	if err == gorm.ErrRecordNotFound {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Follower not found"})
-		return
-	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	removed, err := models.RemoveFollow(db, uint(targetUserID), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove follower"})
 		return
 	}
 
-	if err := db.Delete(&follow).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove follower"})
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follower not found"})
 		return
 	}
 
-	db.Model(&models.User{}).
-		Where("id = ? AND followers_count > 0", userID).
-		Update("followers_count", gorm.Expr("followers_count - 1"))
-
-	db.Model(&models.User{}).
-		Where("id = ? AND following_count > 0", targetUserID).
-		Update("following_count", gorm.Expr("following_count - 1"))
-
 	var stillFollowing models.Follow
 	isFollowing := db.Where(
 		"follower_id = ? AND following_id = ?",
@@ -0,0 +1,585 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxGiftQuantity = 100
+
+func GetGiftCatalog(c *gin.Context) {
+	var giftTypes []models.GiftType
+	if err := config.DB.Where("is_active = ?", true).
+		Order("position ASC, coin_price ASC").
+		Find(&giftTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch gifts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"gifts":   giftTypes,
+	})
+}
+
+func GetCoinPacks(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"packs":            services.CoinPacks,
+		"payments_enabled": services.Payments != nil,
+	})
+}
+
+func GetWallet(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+	giftService := services.NewGiftService(db, services.Payments)
+
+	coins, err := giftService.GetWallet(db, userID, models.WalletKindCoins)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load wallet"})
+		return
+	}
+	earnings, err := giftService.GetWallet(db, userID, models.WalletKindEarnings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load wallet"})
+		return
+	}
+
+	var user models.User
+	db.Select("id, total_gifts_value").First(&user, userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"coins":             coins.Balance,
+		"earnings":          earnings.Balance,
+		"total_gifts_value": user.TotalGiftsValue,
+	})
+}
+
+// GetWalletTransactions lists the ledger entries on the user's wallets,
+// newest first.
+func GetWalletTransactions(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c, createdAtKeys("ledger_entries")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var entries []models.LedgerEntry
+	if err := pagination.Apply(db.
+		Joins("JOIN wallets ON wallets.id = ledger_entries.wallet_id").
+		Where("wallets.user_id = ?", userID)).
+		Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+		return
+	}
+
+	entries, nextCursor := finishPage(pagination, entries, func(e models.LedgerEntry) []interface{} {
+		return []interface{}{e.CreatedAt, e.ID}
+	})
+
+	transactionIDs := make([]uint, 0, len(entries))
+	walletIDs := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		transactionIDs = append(transactionIDs, entry.TransactionID)
+		walletIDs = append(walletIDs, entry.WalletID)
+	}
+
+	transactions := map[uint]models.LedgerTransaction{}
+	wallets := map[uint]models.Wallet{}
+	if len(entries) > 0 {
+		var txList []models.LedgerTransaction
+		db.Where("id IN ?", transactionIDs).Find(&txList)
+		for _, txn := range txList {
+			transactions[txn.ID] = txn
+		}
+		var walletList []models.Wallet
+		db.Where("id IN ?", walletIDs).Find(&walletList)
+		for _, wallet := range walletList {
+			wallets[wallet.ID] = wallet
+		}
+	}
+
+	results := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		txn := transactions[entry.TransactionID]
+		results = append(results, gin.H{
+			"id":             entry.ID,
+			"transaction_id": entry.TransactionID,
+			"kind":           txn.Kind,
+			"wallet":         wallets[entry.WalletID].Kind,
+			"amount":         entry.Amount,
+			"memo":           txn.Memo,
+			"created_at":     entry.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"transactions": results,
+		"limit":        pagination.Limit,
+		"has_more":     nextCursor != nil,
+		"next_cursor":  nextCursor,
+	})
+}
+
+func TopUpWallet(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req struct {
+		PackID       string `json:"pack_id" binding:"required"`
+		PaymentToken string `json:"payment_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pack_id and payment_token are required"})
+		return
+	}
+
+	pack, ok := services.FindCoinPack(req.PackID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown coin pack"})
+		return
+	}
+
+	giftService := services.NewGiftService(config.DB, services.Payments)
+	txn, err := giftService.TopUp(userID, pack, req.PaymentToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentsNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Top-ups are not available"})
+		case errors.Is(err, services.ErrPaymentDeclined):
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": "Payment was declined"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to top up wallet"})
+		}
+		return
+	}
+
+	coins, _ := giftService.GetWallet(config.DB, userID, models.WalletKindCoins)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"transaction_id": txn.ID,
+		"coins_added":    pack.Coins,
+		"coins":          coins.Balance,
+	})
+}
+
+type sendGiftRequest struct {
+	GiftType string `json:"gift_type" binding:"required"`
+	Quantity int    `json:"quantity"`
+	Message  string `json:"message"`
+}
+
+func SendRoomGift(c *gin.Context) {
+	db := config.DB
+
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var room models.Room
+	if err := db.Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished).First(&room, roomID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	sendGift(c, room.HostID, models.GiftTargetRoom, room.ID)
+}
+
+func SendCommunityPostGift(c *gin.Context) {
+	db := config.DB
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var post models.CommunityPost
+	if err := db.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	sendGift(c, post.UserID, models.GiftTargetCommunityPost, post.ID)
+}
+
+func sendGift(c *gin.Context, recipientID uint, targetType string, targetID uint) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var req sendGiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "gift_type is required"})
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	if req.Quantity < 1 || req.Quantity > maxGiftQuantity {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity must be between 1 and 100"})
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if len([]rune(req.Message)) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message must be at most 200 characters"})
+		return
+	}
+
+	if recipientID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot send gifts to yourself"})
+		return
+	}
+
+	var hidden int64
+	db.Model(&models.HiddenUser{}).
+		Where("user_id = ? AND hidden_user_id = ?", recipientID, userID).
+		Count(&hidden)
+	if hidden > 0 {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":         "You cannot interact with this content",
+			"is_restricted": true,
+		})
+		return
+	}
+
+	var giftType models.GiftType
+	if err := db.Where("is_active = ?", true).
+		Where("slug = ? OR CAST(id AS TEXT) = ?", req.GiftType, req.GiftType).
+		First(&giftType).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown gift type"})
+		return
+	}
+
+	var sender models.User
+	if err := db.First(&sender, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	giftService := services.NewGiftService(db, services.Payments)
+	gift, err := giftService.SendGift(services.SendGiftInput{
+		Sender:      sender,
+		RecipientID: recipientID,
+		GiftType:    giftType,
+		Quantity:    req.Quantity,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Message:     req.Message,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientBalance) {
+			coins, _ := giftService.GetWallet(db, userID, models.WalletKindCoins)
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":    "Not enough coins",
+				"required": giftType.CoinPrice * int64(req.Quantity),
+				"balance":  coins.Balance,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send gift"})
+		return
+	}
+
+	coins, _ := giftService.GetWallet(db, userID, models.WalletKindCoins)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"gift":    gift,
+		"coins":   coins.Balance,
+	})
+}
+
+func GetRoomGifts(c *gin.Context) {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+	listGifts(c, models.GiftTargetRoom, uint(roomID))
+}
+
+func GetCommunityPostGifts(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+	listGifts(c, models.GiftTargetCommunityPost, uint(postID))
+}
+
+func listGifts(c *gin.Context, targetType string, targetID uint) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c, createdAtKeys("gifts")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.Model(&models.Gift{}).
+		Where("target_type = ? AND target_id = ? AND refunded_at IS NULL", targetType, targetID)
+
+	var totals struct {
+		Count int64
+		Coins int64
+	}
+	query.Select("COUNT(*) AS count, COALESCE(SUM(coin_amount), 0) AS coins").Scan(&totals)
+
+	blockedBy, err := GetUsersWhoHidMe(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden relations"})
+		return
+	}
+
+	listQuery := db.Where("target_type = ? AND target_id = ? AND refunded_at IS NULL", targetType, targetID)
+	if len(blockedBy) > 0 {
+		listQuery = listQuery.Where("sender_id NOT IN ?", blockedBy)
+	}
+
+	var gifts []models.Gift
+	if err := pagination.Apply(listQuery.Preload("Sender").Preload("GiftType")).Find(&gifts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch gifts"})
+		return
+	}
+
+	gifts, nextCursor := finishPage(pagination, gifts, func(g models.Gift) []interface{} {
+		return []interface{}{g.CreatedAt, g.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"gifts":       gifts,
+		"total_gifts": totals.Count,
+		"total_coins": totals.Coins,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+func AdminRefundGift(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+
+	giftID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gift ID"})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	c.ShouldBindJSON(&req)
+
+	gift, err := services.NewGiftService(config.DB, services.Payments).RefundGift(uint(giftID), adminID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gift not found"})
+		case errors.Is(err, services.ErrAlreadyRefunded):
+			c.JSON(http.StatusConflict, gin.H{"error": "Gift was already refunded"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refund gift"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"gift":    gift,
+	})
+}
+
+func AdminRefundPurchase(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+
+	transactionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	c.ShouldBindJSON(&req)
+
+	refund, err := services.NewGiftService(config.DB, services.Payments).RefundPurchase(uint(transactionID), adminID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		case errors.Is(err, services.ErrNotRefundable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only purchases can be refunded"})
+		case errors.Is(err, services.ErrAlreadyRefunded):
+			c.JSON(http.StatusConflict, gin.H{"error": "Purchase was already refunded"})
+		case errors.Is(err, services.ErrInsufficientBalance):
+			c.JSON(http.StatusConflict, gin.H{"error": "The purchased coins have already been spent"})
+		case errors.Is(err, services.ErrPaymentsNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Payments are not configured"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refund purchase"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"transaction": refund,
+	})
+}
+
+func AdminGetGiftTypes(c *gin.Context) {
+	var giftTypes []models.GiftType
+	if err := config.DB.Order("position ASC, coin_price ASC").Find(&giftTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch gift types"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"gift_types": giftTypes,
+	})
+}
+
+type giftTypeRequest struct {
+	Slug      *string `json:"slug"`
+	Name      *string `json:"name"`
+	IconURL   *string `json:"icon_url"`
+	CoinPrice *int64  `json:"coin_price"`
+	Position  *int    `json:"position"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+func AdminCreateGiftType(c *gin.Context) {
+	var req giftTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == nil || req.CoinPrice == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and coin_price are required"})
+		return
+	}
+	if req.Slug == nil {
+		req.Slug = req.Name
+	}
+
+	giftType := models.GiftType{IsActive: true}
+	if errMsg := applyGiftTypeRequest(&giftType, req); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.GiftType{}).Where("slug = ?", giftType.Slug).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A gift type with this slug already exists"})
+		return
+	}
+
+	if err := config.DB.Create(&giftType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create gift type"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":   true,
+		"gift_type": giftType,
+	})
+}
+
+// AdminUpdateGiftType edits a catalogue entry. Price changes only affect new
+// gifts; sent gifts keep the coin amount recorded in the ledger.
+func AdminUpdateGiftType(c *gin.Context) {
+	db := config.DB
+
+	giftTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gift type ID"})
+		return
+	}
+
+	var giftType models.GiftType
+	if err := db.First(&giftType, giftTypeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gift type not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	var req giftTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errMsg := applyGiftTypeRequest(&giftType, req); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	var count int64
+	db.Model(&models.GiftType{}).Where("slug = ? AND id <> ?", giftType.Slug, giftType.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A gift type with this slug already exists"})
+		return
+	}
+
+	if err := db.Save(&giftType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update gift type"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"gift_type": giftType,
+	})
+}
+
+func applyGiftTypeRequest(giftType *models.GiftType, req giftTypeRequest) string {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 64 {
+			return "Name must be between 1 and 64 characters"
+		}
+		giftType.Name = name
+	}
+	if req.Slug != nil {
+		slug := models.Slugify(*req.Slug)
+		if slug == "" || len(slug) > 64 {
+			return "Slug must contain letters or digits and be at most 64 characters"
+		}
+		giftType.Slug = slug
+	}
+	if req.IconURL != nil {
+		giftType.IconURL = strings.TrimSpace(*req.IconURL)
+	}
+	if req.CoinPrice != nil {
+		if *req.CoinPrice < 1 {
+			return "coin_price must be at least 1"
+		}
+		giftType.CoinPrice = *req.CoinPrice
+	}
+	if req.Position != nil {
+		giftType.Position = *req.Position
+	}
+	if req.IsActive != nil {
+		giftType.IsActive = *req.IsActive
+	}
+	return ""
+}
@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type hashtagWithCounts struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	RoomCount  int64  `json:"room_count"`
+	PostCount  int64  `json:"post_count"`
+	TotalCount int64  `json:"total_count"`
+}
+
+// hashtagCountsSQL counts the visible rooms and posts using each tag. Callers
+// add the WHERE, ORDER BY and LIMIT.
+const hashtagCountsSQL = `
+	SELECT hashtags.id, hashtags.name, counts.room_count, counts.post_count,
+		counts.room_count + counts.post_count AS total_count
+	FROM hashtags
+	CROSS JOIN LATERAL (
+		SELECT
+			(SELECT COUNT(*) FROM room_hashtags rh
+				JOIN rooms ON rooms.id = rh.room_id
+				WHERE rh.hashtag_id = hashtags.id
+					AND rooms.deleted_at IS NULL AND rooms.is_private = false AND rooms.is_hidden = false AND rooms.status = 'published') AS room_count,
+			(SELECT COUNT(*) FROM community_post_hashtags ph
+				JOIN community_posts p ON p.id = ph.community_post_id
+				WHERE ph.hashtag_id = hashtags.id AND p.deleted_at IS NULL) AS post_count
+	) counts
+`
+
+func GetHashtag(c *gin.Context) {
+	name := models.NormalizeHashtag(c.Param("name"))
+
+	var tag hashtagWithCounts
+	if err := config.DB.Raw(hashtagCountsSQL+" WHERE hashtags.name = ?", name).Scan(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hashtag"})
+		return
+	}
+	if tag.ID == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Hashtag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"hashtag": tag,
+	})
+}
+
+// SearchHashtags matches tags by prefix, most used first.
+func SearchHashtags(c *gin.Context) {
+	query := models.NormalizeHashtag(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	tags := []hashtagWithCounts{}
+	if err := config.DB.Raw(hashtagCountsSQL+`
+		WHERE hashtags.name LIKE ? ESCAPE '\'
+		ORDER BY total_count DESC, hashtags.name ASC
+		LIMIT ?`, escapeLikePattern(query)+"%", limit).
+		Scan(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search hashtags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"query":    query,
+		"hashtags": tags,
+	})
+}
+
+// GetTrendingHashtags ranks tags by how many visible rooms and posts were
+// tagged with them inside the window (default 7 days).
+func GetTrendingHashtags(c *gin.Context) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "168"))
+	if hours < 1 || hours > 24*30 {
+		hours = 168
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+
+	type trendingHashtag struct {
+		ID        uint   `json:"id"`
+		Name      string `json:"name"`
+		RoomCount int64  `json:"room_count"`
+		PostCount int64  `json:"post_count"`
+		Uses      int64  `json:"uses"`
+	}
+
+	tags := []trendingHashtag{}
+	if err := config.DB.Raw(`
+		WITH uses AS (
+			SELECT rh.hashtag_id, 1 AS is_room
+			FROM room_hashtags rh
+			JOIN rooms ON rooms.id = rh.room_id
+			WHERE rh.created_at > @since
+				AND rooms.deleted_at IS NULL AND rooms.is_private = false AND rooms.is_hidden = false AND rooms.status = 'published'
+			UNION ALL
+			SELECT ph.hashtag_id, 0
+			FROM community_post_hashtags ph
+			JOIN community_posts p ON p.id = ph.community_post_id
+			WHERE ph.created_at > @since AND p.deleted_at IS NULL
+		)
+		SELECT hashtags.id, hashtags.name,
+			SUM(uses.is_room) AS room_count,
+			COUNT(*) - SUM(uses.is_room) AS post_count,
+			COUNT(*) AS uses
+		FROM uses
+		JOIN hashtags ON hashtags.id = uses.hashtag_id
+		GROUP BY hashtags.id, hashtags.name
+		ORDER BY uses DESC, hashtags.name ASC
+		LIMIT @limit
+	`, map[string]interface{}{"since": since, "limit": limit}).Scan(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trending hashtags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"hours":    hours,
+		"hashtags": tags,
+	})
+}
+
+func GetHashtagRooms(c *gin.Context) {
+	db := config.DB
+	viewerID := c.GetUint("user_id")
+
+	tagID, ok := findHashtagID(c, db)
+	if !ok {
+		return
+	}
+
+	pagination, err := parsePagination(c, createdAtKeys("rooms")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.Model(&models.Room{}).
+		Joins("JOIN room_hashtags ON room_hashtags.room_id = rooms.id").
+		Where("room_hashtags.hashtag_id = ?", tagID).
+		Where("rooms.is_private = ? AND rooms.is_hidden = ? AND rooms.status = ?", false, false, models.RoomStatusPublished)
+
+	if viewerID > 0 {
+		blockedBy, err := GetUsersWhoHidMe(db, viewerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden relations"})
+			return
+		}
+		if len(blockedBy) > 0 {
+			query = query.Where("rooms.host_id NOT IN ?", blockedBy)
+		}
+	}
+
+	var rooms []models.Room
+	if err := pagination.Apply(query.Preload("Host")).Find(&rooms).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms"})
+		return
+	}
+
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"rooms":       rooms,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+func GetHashtagPosts(c *gin.Context) {
+	db := config.DB
+	viewerID := c.GetUint("user_id")
+
+	tagID, ok := findHashtagID(c, db)
+	if !ok {
+		return
+	}
+
+	pagination, err := parsePagination(c, createdAtKeys("community_posts")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := db.Model(&models.CommunityPost{}).
+		Joins("JOIN community_post_hashtags ON community_post_hashtags.community_post_id = community_posts.id").
+		Where("community_post_hashtags.hashtag_id = ?", tagID)
+
+	if viewerID > 0 {
+		blockedBy, err := GetUsersWhoHidMe(db, viewerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden relations"})
+			return
+		}
+		if len(blockedBy) > 0 {
+			query = query.Where("community_posts.user_id NOT IN ?", blockedBy)
+		}
+	}
+
+	var posts []models.CommunityPost
+	if err := pagination.Apply(query.Preload("User").Preload("Mentions")).Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	posts, nextCursor := finishPage(pagination, posts, func(p models.CommunityPost) []interface{} {
+		return []interface{}{p.CreatedAt, p.ID}
+	})
+
+	postIDs := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	images := map[uint][]models.CommunityPostImage{}
+	liked := map[uint]bool{}
+	if len(postIDs) > 0 {
+		var imageList []models.CommunityPostImage
+		db.Where("community_post_id IN ?", postIDs).Order("position ASC").Find(&imageList)
+		for _, image := range imageList {
+			images[image.CommunityPostID] = append(images[image.CommunityPostID], image)
+		}
+
+		if viewerID > 0 {
+			var likedIDs []uint
+			db.Model(&models.CommunityPostLike{}).
+				Where("user_id = ? AND community_post_id IN ?", viewerID, postIDs).
+				Pluck("community_post_id", &likedIDs)
+			for _, id := range likedIDs {
+				liked[id] = true
+			}
+		}
+	}
+
+	results := make([]gin.H, 0, len(posts))
+	for _, post := range posts {
+		postImages := images[post.ID]
+		if postImages == nil {
+			postImages = []models.CommunityPostImage{}
+		}
+		results = append(results, gin.H{
+			"id":             post.ID,
+			"user_id":        post.UserID,
+			"user":           post.User,
+			"content":        post.Content,
+			"audio_url":      post.AudioURL,
+			"duration":       post.Duration,
+			"images":         postImages,
+			"likes_count":    post.LikesCount,
+			"comments_count": post.CommentsCount,
+			"mentions":       post.Mentions,
+			"is_liked":       liked[post.ID],
+			"created_at":     post.CreatedAt,
+			"updated_at":     post.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"posts":       results,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+func findHashtagID(c *gin.Context, db *gorm.DB) (uint, bool) {
+	name := models.NormalizeHashtag(c.Param("name"))
+	if name == "" || strings.ContainsAny(name, " \t") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hashtag"})
+		return 0, false
+	}
+
+	var tag models.Hashtag
+	if err := db.Where("name = ?", name).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hashtag not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return 0, false
+	}
+	return tag.ID, true
+}
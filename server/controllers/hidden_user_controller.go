@@ -154,9 +154,11 @@ func GetHiddenUsers(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("hidden_users")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var total int64
 	db.Model(&models.HiddenUser{}).
@@ -164,17 +166,18 @@ func GetHiddenUsers(c *gin.Context) {
 		Count(&total)
 
 	var hiddenUsers []models.HiddenUser
-	if err := db.
+	if err := pagination.Apply(db.
 		Preload("HiddenUser").
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+		Where("user_id = ?", userID)).
 		Find(&hiddenUsers).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden users"})
 		return
 	}
 
+	hiddenUsers, nextCursor := finishPage(pagination, hiddenUsers, func(hu models.HiddenUser) []interface{} {
+		return []interface{}{hu.CreatedAt, hu.ID}
+	})
+
 	users := make([]map[string]interface{}, len(hiddenUsers))
 	for i, hu := range hiddenUsers {
 		users[i] = map[string]interface{}{
@@ -188,10 +191,11 @@ func GetHiddenUsers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"hidden_users": users,
-		"page":         page,
-		"limit":        limit,
+		"page":         pagination.Page,
+		"limit":        pagination.Limit,
 		"total":        total,
-		"has_more":     offset+len(users) < int(total),
+		"has_more":     nextCursor != nil,
+		"next_cursor":  nextCursor,
 	})
 }
 
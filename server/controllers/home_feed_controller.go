@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// feedItemRow is one entry of the merged home feed before its content is
+// loaded. Score includes live like, listen and comment counts, so it only
+// ranks items within a page; the cursor walks the immutable
+// (created_at, kind_order, item_id) order.
+type feedItemRow struct {
+	Type      string
+	KindOrder int
+	ItemID    uint
+	ActorID   uint
+	CreatedAt time.Time
+	Score     float64
+}
+
+// GetHomeFeed merges new rooms and community posts from followed creators
+// with rooms those creators liked into one cursor-paginated timeline. Pages
+// are consecutive slices of the timeline, each ranked by score.
+// Seen items (marked via MarkFeedItemsSeen, or rooms already listened to) are
+// left out unless include_seen=true.
+func GetHomeFeed(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c,
+		SortKey{Column: "feed_items.created_at", Desc: true},
+		SortKey{Column: "feed_items.kind_order", Desc: true},
+		SortKey{Column: "feed_items.item_id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiddenBy, err := GetUsersWhoHidMe(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden relations"})
+		return
+	}
+	if len(hiddenBy) == 0 {
+		hiddenBy = []uint{0}
+	}
+
+	includeSeen := c.Query("include_seen") == "true"
+	since := time.Now().Add(-models.FeedWindow)
+
+	var rows []feedItemRow
+	if err := pagination.Apply(db.Table("(?) AS feed_items", homeFeedQuery(db, userID, hiddenBy, since, includeSeen))).
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load feed"})
+		return
+	}
+
+	rows, nextCursor := finishPage(pagination, rows, func(row feedItemRow) []interface{} {
+		return []interface{}{row.CreatedAt, row.KindOrder, row.ItemID}
+	})
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Score > rows[j].Score })
+
+	items, err := loadHomeFeedItems(db, userID, rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"items":       items,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+func homeFeedQuery(db *gorm.DB, userID uint, hiddenBy []uint, since time.Time, includeSeen bool) *gorm.DB {
+	roomSeen := "FALSE"
+	postSeen := "FALSE"
+	if !includeSeen {
+		roomSeen = `(
+			EXISTS (SELECT 1 FROM feed_seen_items s WHERE s.user_id = @user AND s.item_type = 'room' AND s.item_id = rooms.id)
+			OR EXISTS (SELECT 1 FROM listen_history lh WHERE lh.user_id = @user AND lh.room_id = rooms.id AND lh.deleted_at IS NULL)
+		)`
+		postSeen = `EXISTS (SELECT 1 FROM feed_seen_items s WHERE s.user_id = @user AND s.item_type = 'community_post' AND s.item_id = p.id)`
+	}
+
+	return db.Raw(`
+		WITH followed AS (
+			SELECT following_id AS id FROM follows WHERE follower_id = @user
+		)
+		SELECT 'room' AS type, 3 AS kind_order, rooms.id AS item_id, rooms.host_id AS actor_id, rooms.created_at,
+			0.3 * LN(1 + rooms.likes_count + rooms.total_listens / 10.0) + EXTRACT(EPOCH FROM rooms.created_at) / 45000 AS score
+		FROM rooms
+		WHERE rooms.deleted_at IS NULL
+			AND rooms.is_private = false
+			AND rooms.is_hidden = false
+			AND rooms.status = 'published'
+			AND rooms.created_at > @since
+			AND rooms.host_id IN (SELECT id FROM followed)
+			AND rooms.host_id NOT IN @hidden_by
+			AND NOT `+roomSeen+`
+
+		UNION ALL
+
+		SELECT 'community_post', 2, p.id, p.user_id, p.created_at,
+			0.3 * LN(1 + p.likes_count + p.comments_count) + EXTRACT(EPOCH FROM p.created_at) / 45000
+		FROM community_posts p
+		WHERE p.deleted_at IS NULL
+			AND p.created_at > @since
+			AND p.user_id IN (SELECT id FROM followed)
+			AND p.user_id NOT IN @hidden_by
+			AND NOT `+postSeen+`
+
+		UNION ALL
+
+		SELECT 'liked_room', 1, liked.room_id, liked.user_id, liked.created_at,
+			0.5 * LN(1 + liked.followed_likes) + EXTRACT(EPOCH FROM liked.created_at) / 45000 - 0.5
+		FROM (
+			SELECT DISTINCT ON (rl.room_id) rl.room_id, rl.user_id, rl.created_at,
+				COUNT(*) OVER (PARTITION BY rl.room_id) AS followed_likes
+			FROM room_likes rl
+			INNER JOIN rooms ON rooms.id = rl.room_id
+			WHERE rl.created_at > @since
+				AND rl.user_id IN (SELECT id FROM followed)
+				AND rl.user_id NOT IN @hidden_by
+				AND rooms.deleted_at IS NULL
+				AND rooms.is_private = false
+				AND rooms.is_hidden = false
+				AND rooms.status = 'published'
+				AND rooms.host_id <> @user
+				AND rooms.host_id NOT IN @hidden_by
+				AND rooms.host_id NOT IN (SELECT id FROM followed)
+				AND NOT `+roomSeen+`
+			ORDER BY rl.room_id, rl.created_at ASC
+		) liked
+	`, map[string]interface{}{
+		"user":      userID,
+		"hidden_by": hiddenBy,
+		"since":     since,
+	})
+}
+
+func loadHomeFeedItems(db *gorm.DB, userID uint, rows []feedItemRow) ([]gin.H, error) {
+	var roomIDs, postIDs, actorIDs []uint
+	for _, row := range rows {
+		if row.Type == models.FeedItemCommunityPost {
+			postIDs = append(postIDs, row.ItemID)
+		} else {
+			roomIDs = append(roomIDs, row.ItemID)
+		}
+		actorIDs = append(actorIDs, row.ActorID)
+	}
+
+	rooms := map[uint]models.Room{}
+	if len(roomIDs) > 0 {
+		var list []models.Room
+		if err := db.Preload("Host").Where("id IN ?", roomIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, room := range list {
+			rooms[room.ID] = room
+		}
+	}
+
+	posts := map[uint]models.CommunityPost{}
+	images := map[uint][]models.CommunityPostImage{}
+	liked := map[uint]bool{}
+	if len(postIDs) > 0 {
+		var list []models.CommunityPost
+		if err := db.Preload("User").Preload("Mentions").Where("id IN ?", postIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, post := range list {
+			posts[post.ID] = post
+		}
+
+		var imageList []models.CommunityPostImage
+		db.Where("community_post_id IN ?", postIDs).Order("position ASC").Find(&imageList)
+		for _, image := range imageList {
+			images[image.CommunityPostID] = append(images[image.CommunityPostID], image)
+		}
+
+		var likedIDs []uint
+		db.Model(&models.CommunityPostLike{}).
+			Where("user_id = ? AND community_post_id IN ?", userID, postIDs).
+			Pluck("community_post_id", &likedIDs)
+		for _, id := range likedIDs {
+			liked[id] = true
+		}
+	}
+
+	actors := map[uint]models.User{}
+	if len(actorIDs) > 0 {
+		var list []models.User
+		if err := db.Where("id IN ?", actorIDs).Find(&list).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range list {
+			actors[user.ID] = user
+		}
+	}
+
+	items := make([]gin.H, 0, len(rows))
+	for _, row := range rows {
+		actor := actors[row.ActorID]
+		item := gin.H{
+			"type":       row.Type,
+			"id":         row.ItemID,
+			"created_at": row.CreatedAt,
+			"score":      row.Score,
+			"actor": gin.H{
+				"id":          actor.ID,
+				"username":    actor.Username,
+				"full_name":   actor.FullName,
+				"profile_pic": actor.ProfilePic,
+				"is_verified": actor.IsVerified,
+			},
+		}
+
+		switch row.Type {
+		case models.FeedItemCommunityPost:
+			post, ok := posts[row.ItemID]
+			if !ok {
+				continue
+			}
+			postImages := images[post.ID]
+			if postImages == nil {
+				postImages = []models.CommunityPostImage{}
+			}
+			item["post"] = gin.H{
+				"id":             post.ID,
+				"user_id":        post.UserID,
+				"user":           post.User,
+				"content":        post.Content,
+				"audio_url":      post.AudioURL,
+				"duration":       post.Duration,
+				"images":         postImages,
+				"likes_count":    post.LikesCount,
+				"comments_count": post.CommentsCount,
+				"mentions":       post.Mentions,
+				"is_liked":       liked[post.ID],
+				"created_at":     post.CreatedAt,
+				"updated_at":     post.UpdatedAt,
+			}
+		default:
+			room, ok := rooms[row.ItemID]
+			if !ok {
+				continue
+			}
+			item["room"] = room
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+type MarkFeedSeenRequest struct {
+	Items []struct {
+		Type string `json:"type" binding:"required"`
+		ID   uint   `json:"id" binding:"required"`
+	} `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// MarkFeedItemsSeen records items the client has shown so later feed pages
+// and refreshes skip them.
+func MarkFeedItemsSeen(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req MarkFeedSeenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items must contain between 1 and 100 {type, id} entries"})
+		return
+	}
+
+	now := time.Now()
+	seen := make([]models.FeedSeenItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		itemType := item.Type
+		switch itemType {
+		case models.FeedItemRoom, models.FeedItemLikedRoom:
+			itemType = models.FeedItemRoom
+		case models.FeedItemCommunityPost:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown feed item type: " + item.Type})
+			return
+		}
+
+		seen = append(seen, models.FeedSeenItem{
+			UserID:   userID,
+			ItemType: itemType,
+			ItemID:   item.ID,
+			SeenAt:   now,
+		})
+	}
+
+	if err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "item_type"}, {Name: "item_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"seen_at"}),
+	}).Create(&seen).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark items as seen"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"marked":  len(seen),
+	})
+}
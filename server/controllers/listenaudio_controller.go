@@ -6,6 +6,7 @@ import (
 	"time"
 	"voxarena_server/config"
 	"voxarena_server/models"
+	"voxarena_server/services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -30,7 +31,7 @@ func StartListening(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var room models.Room
-	if err := config.DB.First(&room, roomID).Error; err != nil {
+	if err := config.DB.First(&room, roomID).Error; err != nil || !room.IsVisibleTo(userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
@@ -53,12 +54,20 @@ func StartListening(c *gin.Context) {
 		return
 	}
 
+	// Let the player resume where another device left off.
+	resumePosition := 0
+	var state models.PlaybackState
+	if err := config.DB.Where("user_id = ? AND room_id = ?", userID, roomID).First(&state).Error; err == nil && !state.IsFinished {
+		resumePosition = state.Position
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":        "Started listening",
-		"listener_count": room.ListenerCount + 1,
-		"total_listens":  room.TotalListens,
-		"is_live":        room.IsLive,
-		"history_id":     listenHistory.ID,
+		"message":         "Started listening",
+		"listener_count":  room.ListenerCount + 1,
+		"total_listens":   room.TotalListens,
+		"is_live":         room.IsLive,
+		"history_id":      listenHistory.ID,
+		"resume_position": resumePosition,
 	})
 }
 
@@ -126,6 +135,47 @@ func UpdateListenHistory(c *gin.Context) {
 	})
 }
 
+type PlaybackEventsRequest struct {
+	Events []services.PlaybackEvent `json:"events" binding:"required,min=1,max=200,dive"`
+}
+
+// RecordPlaybackEvents accepts a batch of periodic position and seek pings
+// for a listen; they feed the host's retention curve.
+func RecordPlaybackEvents(c *gin.Context) {
+	historyID := c.Param("id")
+
+	var req PlaybackEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	var history models.ListenHistory
+	if err := config.DB.Where("id = ? AND user_id = ?", historyID, userID).First(&history).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Listen history not found"})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.Select("id, duration").First(&room, history.RoomID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	accepted, err := services.NewRetentionService(config.DB).RecordEvents(history, room.Duration, req.Events)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record playback events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Playback events recorded",
+		"accepted": accepted,
+	})
+}
+
 func GetListenerCount(c *gin.Context) {
 	roomIDStr := c.Param("id")
 	roomID, err := strconv.ParseUint(roomIDStr, 10, 32)
@@ -151,9 +201,14 @@ func GetListenerCount(c *gin.Context) {
 func GetUserListenHistory(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c,
+		SortKey{Column: "listen_history.listened_at", Desc: true},
+		SortKey{Column: "listen_history.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var histories []models.ListenHistory
 	var total int64
@@ -162,7 +217,6 @@ func GetUserListenHistory(c *gin.Context) {
 
 	var blockedBy []uint
 	if userID > 0 {
-		var err error
 		blockedBy, err = GetUsersWhoHidMe(db, userID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -196,7 +250,7 @@ func GetUserListenHistory(c *gin.Context) {
 		return
 	}
 
-	if err := query.
+	if err := pagination.Apply(query.
 		Preload(
 			"Room",
 			"is_live = ? OR is_private = ? OR host_id = ? AND is_hidden = ?",
@@ -205,10 +259,7 @@ func GetUserListenHistory(c *gin.Context) {
 			userID,
 			false,
 		).
-		Preload("Room.Host").
-		Order("listen_history.listened_at DESC").
-		Limit(limit).
-		Offset(offset).
+		Preload("Room.Host")).
 		Find(&histories).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch history",
@@ -216,6 +267,10 @@ func GetUserListenHistory(c *gin.Context) {
 		return
 	}
 
+	histories, nextCursor := finishPage(pagination, histories, func(h models.ListenHistory) []interface{} {
+		return []interface{}{h.ListenedAt, h.ID}
+	})
+
 	filteredHistories := make([]models.ListenHistory, 0)
 	for _, h := range histories {
 		if h.Room.ID != 0 {
@@ -224,11 +279,12 @@ func GetUserListenHistory(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"history":  filteredHistories,
-		"total":    total,
-		"page":     page,
-		"limit":    limit,
-		"has_more": offset+limit < int(total),
+		"history":     filteredHistories,
+		"total":       total,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -32,15 +32,11 @@ func GetNotifications(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("notifications")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	offset := (page - 1) * limit
 
 	blockedBy, err := GetUsersWhoHidMe(config.DB, userID)
 	if err != nil {
@@ -101,23 +97,24 @@ func GetNotifications(c *gin.Context) {
 
 	fetchQuery = fetchQuery.Where(existsFilter)
 
-	if err := fetchQuery.
-		Preload("Actor").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+	if err := pagination.Apply(fetchQuery.Preload("Actor")).
 		Find(&notifications).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
 		return
 	}
 
+	notifications, nextCursor := finishPage(pagination, notifications, func(n models.Notification) []interface{} {
+		return []interface{}{n.CreatedAt, n.ID}
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":       true,
 		"notifications": notifications,
-		"page":          page,
-		"limit":         limit,
+		"page":          pagination.Page,
+		"limit":         pagination.Limit,
 		"total":         total,
-		"has_more":      offset+len(notifications) < int(total),
+		"has_more":      nextCursor != nil,
+		"next_cursor":   nextCursor,
 	})
 }
 
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// SortKey is one column of a list's ordering. The keys of a list must end
+// with a unique column (usually the primary key) so every row has a distinct
+// position.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Pagination holds the paging parameters of a list request. A request either
+// continues from an opaque cursor or, as a deprecated fallback, uses
+// page/limit with OFFSET.
+type Pagination struct {
+	Keys   []SortKey
+	Limit  int
+	Page   int
+	Offset int
+
+	after []interface{}
+}
+
+// parsePagination reads cursor, limit and page from the query string. The
+// cursor wins when both cursor and page are given.
+func parsePagination(c *gin.Context, keys ...SortKey) (*Pagination, error) {
+	p := &Pagination{Keys: keys, Limit: defaultPageLimit, Page: 1}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		p.Limit = limit
+	}
+	if p.Limit > maxPageLimit {
+		p.Limit = maxPageLimit
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		values, err := decodeCursor(cursor, len(keys))
+		if err != nil {
+			return nil, err
+		}
+		p.after = values
+		return p, nil
+	}
+
+	if pageParam := c.Query("page"); pageParam != "" {
+		c.Header("Deprecation", "true")
+		if page, err := strconv.Atoi(pageParam); err == nil && page > 1 {
+			p.Page = page
+			p.Offset = (page - 1) * p.Limit
+		}
+	}
+
+	return p, nil
+}
+
+// Apply orders query by the sort keys, continues after the cursor (or skips
+// Offset rows) and fetches one extra row so finishPage can tell whether there
+// is a next page.
+func (p *Pagination) Apply(query *gorm.DB) *gorm.DB {
+	orders := make([]string, 0, len(p.Keys))
+	for _, key := range p.Keys {
+		if key.Desc {
+			orders = append(orders, key.Column+" DESC")
+		} else {
+			orders = append(orders, key.Column+" ASC")
+		}
+	}
+	query = query.Order(strings.Join(orders, ", "))
+
+	if p.after != nil {
+		condition, args := p.keysetCondition()
+		query = query.Where(condition, args...)
+	} else if p.Offset > 0 {
+		query = query.Offset(p.Offset)
+	}
+
+	return query.Limit(p.Limit + 1)
+}
+
+// keysetCondition builds "(k1 < v1) OR (k1 = v1 AND k2 < v2) OR ...", with the
+// comparison flipped for ascending keys.
+func (p *Pagination) keysetCondition() (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	for i, key := range p.Keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, p.Keys[j].Column+" = ?")
+			args = append(args, p.after[j])
+		}
+
+		op := ">"
+		if key.Desc {
+			op = "<"
+		}
+		parts = append(parts, key.Column+" "+op+" ?")
+		args = append(args, p.after[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// finishPage drops the extra row fetched by Apply and returns the cursor for
+// the next page, or nil when this is the last one. cursorValues must return
+// the item's values for the sort keys, in order.
+func finishPage[T any](p *Pagination, items []T, cursorValues func(T) []interface{}) ([]T, *string) {
+	if len(items) <= p.Limit {
+		return items, nil
+	}
+
+	items = items[:p.Limit]
+	cursor := encodeCursor(cursorValues(items[len(items)-1]))
+	return items, &cursor
+}
+
+func encodeCursor(values []interface{}) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor reverses encodeCursor. Sort keys are timestamps and numbers, so
+// strings are read back as RFC3339 times and integral numbers as int64.
+func decodeCursor(cursor string, keyCount int) ([]interface{}, error) {
+	invalid := fmt.Errorf("invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw []interface{}
+	if err := decoder.Decode(&raw); err != nil || len(raw) != keyCount {
+		return nil, invalid
+	}
+
+	values := make([]interface{}, len(raw))
+	for i, value := range raw {
+		switch v := value.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				values[i] = f
+			} else {
+				return nil, invalid
+			}
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, invalid
+			}
+			values[i] = t
+		case bool:
+			values[i] = v
+		default:
+			return nil, invalid
+		}
+	}
+
+	return values, nil
+}
+
+// createdAtKeys is the ordering used by most lists: newest first.
+func createdAtKeys(table string) []SortKey {
+	return []SortKey{
+		{Column: table + ".created_at", Desc: true},
+		{Column: table + ".id", Desc: true},
+	}
+}
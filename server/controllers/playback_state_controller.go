@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlaybackStateRequest struct {
+	Position  int        `json:"position" binding:"min=0"`
+	Device    string     `json:"device" binding:"max=100"`
+	Finished  bool       `json:"finished"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// GetPlaybackState returns the caller's resume point in a room, or null if
+// they haven't played it yet.
+func GetPlaybackState(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var state models.PlaybackState
+	if err := config.DB.Where("user_id = ? AND room_id = ?", userID, roomID).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, gin.H{"playback_state": nil})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"playback_state": state})
+}
+
+// UpdatePlaybackState saves the caller's position in a room. Writes carry the
+// device's updated_at; a write older than the stored state is ignored and the
+// stored state is returned with applied=false so the device can catch up.
+func UpdatePlaybackState(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	var req PlaybackStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.Select("id, duration, host_id, status").First(&room, roomID).Error; err != nil || !room.IsVisibleTo(userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	// Device clocks running ahead would otherwise win every later conflict.
+	now := time.Now()
+	updatedAt := now
+	if req.UpdatedAt != nil && req.UpdatedAt.Before(now) {
+		updatedAt = *req.UpdatedAt
+	}
+
+	position := req.Position
+	if room.Duration > 0 && position > room.Duration {
+		position = room.Duration
+	}
+
+	state := models.PlaybackState{
+		UserID:     userID,
+		RoomID:     room.ID,
+		Position:   position,
+		Device:     strings.TrimSpace(req.Device),
+		IsFinished: req.Finished,
+		UpdatedAt:  updatedAt,
+	}
+
+	result := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "room_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "device", "is_finished", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "playback_states.updated_at < excluded.updated_at"},
+		}},
+	}).Create(&state)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save playback state"})
+		return
+	}
+
+	var current models.PlaybackState
+	if err := config.DB.Where("user_id = ? AND room_id = ?", userID, room.ID).First(&current).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playback state"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applied":        result.RowsAffected > 0,
+		"playback_state": current,
+	})
+}
+
+// GetContinueListening lists rooms the caller started but hasn't finished,
+// most recently played first.
+func GetContinueListening(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c,
+		SortKey{Column: "playback_states.updated_at", Desc: true},
+		SortKey{Column: "playback_states.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := config.DB
+
+	hiddenByUserIDs, err := GetUsersWhoHidMe(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden users"})
+		return
+	}
+
+	query := db.Model(&models.PlaybackState{}).
+		Joins("JOIN rooms ON rooms.id = playback_states.room_id AND rooms.deleted_at IS NULL").
+		Where("playback_states.user_id = ?", userID).
+		Where("playback_states.is_finished = ?", false).
+		Where("playback_states.position > 0").
+		Where("rooms.is_private = ? OR rooms.host_id = ?", false, userID).
+		Where("rooms.status = ? OR rooms.host_id = ?", models.RoomStatusPublished, userID).
+		Where("rooms.is_hidden = ?", false)
+
+	if len(hiddenByUserIDs) > 0 {
+		query = query.Where("rooms.host_id NOT IN ?", hiddenByUserIDs)
+	}
+
+	var states []models.PlaybackState
+	if err := pagination.Apply(query.
+		Preload("Room").
+		Preload("Room.Host")).
+		Find(&states).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch continue listening"})
+		return
+	}
+
+	states, nextCursor := finishPage(pagination, states, func(s models.PlaybackState) []interface{} {
+		return []interface{}{s.UpdatedAt, s.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       states,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
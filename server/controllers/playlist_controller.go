@@ -0,0 +1,962 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxPlaylistTitleLength = 120
+
+var errPlaylistFull = errors.New("playlist is full")
+
+type PlaylistItemRequest struct {
+	RoomID uint `json:"room_id" binding:"required"`
+}
+
+type MovePlaylistItemRequest struct {
+	Position *int `json:"position" binding:"required,min=0"`
+}
+
+// playlistAccess resolves what userID may do with a playlist. Collaborators
+// can view and add items; only the owner manages the playlist itself.
+func playlistAccess(playlist *models.Playlist, userID uint) (canView, canEdit bool, err error) {
+	if userID != 0 && playlist.OwnerID == userID {
+		return true, true, nil
+	}
+
+	if userID != 0 {
+		var hidden int64
+		if err := config.DB.Model(&models.HiddenUser{}).
+			Where("user_id = ? AND hidden_user_id = ?", playlist.OwnerID, userID).
+			Count(&hidden).Error; err != nil {
+			return false, false, err
+		}
+		if hidden > 0 {
+			return false, false, nil
+		}
+
+		isCollaborator, err := models.IsPlaylistCollaborator(config.DB, playlist.ID, userID)
+		if err != nil {
+			return false, false, err
+		}
+		if isCollaborator {
+			return true, true, nil
+		}
+	}
+
+	return playlist.Visibility == models.PlaylistVisibilityPublic, false, nil
+}
+
+func findPlaylist(c *gin.Context) (*models.Playlist, bool) {
+	playlistID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return nil, false
+	}
+
+	var playlist models.Playlist
+	if err := config.DB.Preload("Owner").First(&playlist, playlistID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+	return &playlist, true
+}
+
+// findEditablePlaylist loads the playlist and checks the caller may edit it,
+// or must own it when ownerOnly is set.
+func findEditablePlaylist(c *gin.Context, userID uint, ownerOnly bool) (*models.Playlist, bool) {
+	playlist, ok := findPlaylist(c)
+	if !ok {
+		return nil, false
+	}
+
+	if ownerOnly {
+		if playlist.OwnerID != userID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found or unauthorized"})
+			return nil, false
+		}
+		return playlist, true
+	}
+
+	canView, canEdit, err := playlistAccess(playlist, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return nil, false
+	}
+	if !canEdit {
+		if canView {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can't edit this playlist"})
+		} else {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		}
+		return nil, false
+	}
+	return playlist, true
+}
+
+// playableItems returns the playlist's rooms in order, dropping rooms that
+// are deleted, hidden, private or unpublished to someone else, or hosted by
+// users who hid the viewer.
+func playableItems(playlistID, viewerID uint) ([]models.PlaylistItem, error) {
+	query := config.DB.Model(&models.PlaylistItem{}).
+		Joins("JOIN rooms ON rooms.id = playlist_items.room_id AND rooms.deleted_at IS NULL").
+		Where("playlist_items.playlist_id = ?", playlistID).
+		Where("rooms.is_hidden = ?", false).
+		Where("rooms.is_private = ? OR rooms.host_id = ?", false, viewerID).
+		Where("rooms.status = ? OR rooms.host_id = ?", models.RoomStatusPublished, viewerID)
+
+	if viewerID != 0 {
+		hiddenByUserIDs, err := GetUsersWhoHidMe(config.DB, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if len(hiddenByUserIDs) > 0 {
+			query = query.Where("rooms.host_id NOT IN ?", hiddenByUserIDs)
+		}
+	}
+
+	items := make([]models.PlaylistItem, 0)
+	err := query.
+		Preload("Room").
+		Preload("Room.Host").
+		Order("playlist_items.position ASC, playlist_items.id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func respondWithPlaylist(c *gin.Context, playlist *models.Playlist, userID uint, canEdit bool) {
+	items, err := playableItems(playlist.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlist items"})
+		return
+	}
+
+	var collaborators []models.PlaylistCollaborator
+	if err := config.DB.Preload("User").
+		Where("playlist_id = ?", playlist.ID).
+		Order("created_at ASC").
+		Find(&collaborators).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collaborators"})
+		return
+	}
+
+	isFollowing := false
+	if userID != 0 {
+		var count int64
+		config.DB.Model(&models.PlaylistFollow{}).
+			Where("playlist_id = ? AND user_id = ?", playlist.ID, userID).
+			Count(&count)
+		isFollowing = count > 0
+	}
+
+	response := gin.H{
+		"success":       true,
+		"playlist":      playlist,
+		"items":         items,
+		"collaborators": collaborators,
+		"is_owner":      userID != 0 && playlist.OwnerID == userID,
+		"can_edit":      canEdit,
+		"is_following":  isFollowing,
+	}
+	if userID != 0 && playlist.OwnerID == userID && playlist.ShareToken != nil {
+		response["share_token"] = *playlist.ShareToken
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applyPlaylistForm reads title, description, visibility and cover from a
+// multipart form. Fields that are absent are left unchanged.
+func applyPlaylistForm(c *gin.Context, playlist *models.Playlist, userID uint) bool {
+	if title, ok := c.GetPostForm("title"); ok {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+			return false
+		}
+		if len([]rune(title)) > maxPlaylistTitleLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Title must be at most %d characters", maxPlaylistTitleLength)})
+			return false
+		}
+		playlist.Title = title
+	}
+
+	if description, ok := c.GetPostForm("description"); ok {
+		playlist.Description = strings.TrimSpace(description)
+	}
+
+	if visibility, ok := c.GetPostForm("visibility"); ok {
+		if visibility != models.PlaylistVisibilityPublic && visibility != models.PlaylistVisibilityPrivate {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Visibility must be public or private"})
+			return false
+		}
+		playlist.Visibility = visibility
+	}
+
+	if c.PostForm("remove_cover") == "true" {
+		playlist.CoverURL = ""
+	}
+
+	coverFile, coverHeader, err := c.Request.FormFile("cover")
+	if err == nil {
+		defer coverFile.Close()
+
+		if coverHeader.Size > 5*1024*1024 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cover image must be at most 5MB"})
+			return false
+		}
+
+		coverData, err := io.ReadAll(coverFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read cover image"})
+			return false
+		}
+
+		coverURL, err := utils.UploadThumbnailToCloudinary(coverData, fmt.Sprint(userID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload cover image"})
+			return false
+		}
+		playlist.CoverURL = coverURL
+	}
+
+	return true
+}
+
+func CreatePlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if _, ok := c.GetPostForm("title"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+		return
+	}
+
+	playlist := models.Playlist{
+		OwnerID:    userID,
+		Visibility: models.PlaylistVisibilityPublic,
+	}
+	if !applyPlaylistForm(c, &playlist, userID) {
+		return
+	}
+
+	if err := config.DB.Create(&playlist).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create playlist"})
+		return
+	}
+
+	config.DB.Preload("Owner").First(&playlist, playlist.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"playlist": playlist,
+	})
+}
+
+func UpdatePlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, true)
+	if !ok {
+		return
+	}
+
+	if !applyPlaylistForm(c, playlist, userID) {
+		return
+	}
+
+	if err := config.DB.Model(playlist).Select("title", "description", "visibility", "cover_url").Updates(playlist).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"playlist": playlist,
+	})
+}
+
+func DeletePlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, true)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("playlist_id = ?", playlist.ID).Delete(&models.PlaylistItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("playlist_id = ?", playlist.ID).Delete(&models.PlaylistCollaborator{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("playlist_id = ?", playlist.ID).Delete(&models.PlaylistFollow{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(playlist).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Playlist deleted",
+	})
+}
+
+func GetPlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findPlaylist(c)
+	if !ok {
+		return
+	}
+
+	canView, canEdit, err := playlistAccess(playlist, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	respondWithPlaylist(c, playlist, userID, canEdit)
+}
+
+// GetSharedPlaylist opens a playlist through its share link, regardless of
+// visibility.
+func GetSharedPlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var playlist models.Playlist
+	if err := config.DB.Preload("Owner").Where("share_token = ?", c.Param("token")).First(&playlist).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	_, canEdit, err := playlistAccess(&playlist, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	respondWithPlaylist(c, &playlist, userID, canEdit)
+}
+
+// GetMyPlaylists lists playlists the caller owns or collaborates on. With
+// room_id set, each entry says whether it already holds that room, which is
+// what the "add to playlist" picker needs.
+func GetMyPlaylists(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c,
+		SortKey{Column: "playlists.updated_at", Desc: true},
+		SortKey{Column: "playlists.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.Playlist{}).
+		Where("playlists.owner_id = ? OR playlists.id IN (?)", userID,
+			config.DB.Model(&models.PlaylistCollaborator{}).Select("playlist_id").Where("user_id = ?", userID))
+
+	var playlists []models.Playlist
+	if err := pagination.Apply(query.Preload("Owner")).Find(&playlists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlists"})
+		return
+	}
+
+	playlists, nextCursor := finishPage(pagination, playlists, func(p models.Playlist) []interface{} {
+		return []interface{}{p.UpdatedAt, p.ID}
+	})
+
+	response := gin.H{
+		"success":     true,
+		"playlists":   playlists,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	}
+
+	if roomID := c.Query("room_id"); roomID != "" && len(playlists) > 0 {
+		ids := make([]uint, 0, len(playlists))
+		for _, p := range playlists {
+			ids = append(ids, p.ID)
+		}
+		var containing []uint
+		config.DB.Model(&models.PlaylistItem{}).
+			Where("room_id = ? AND playlist_id IN ?", roomID, ids).
+			Pluck("playlist_id", &containing)
+		if containing == nil {
+			containing = []uint{}
+		}
+		response["containing_room"] = containing
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUserPlaylists lists a user's public playlists, or all of them for the
+// user themselves.
+func GetUserPlaylists(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	pagination, err := parsePagination(c, createdAtKeys("playlists")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var hidden int64
+	config.DB.Model(&models.HiddenUser{}).
+		Where("user_id = ? AND hidden_user_id = ?", targetUserID, userID).
+		Count(&hidden)
+	if hidden > 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"success":     true,
+			"playlists":   []models.Playlist{},
+			"limit":       pagination.Limit,
+			"has_more":    false,
+			"next_cursor": nil,
+		})
+		return
+	}
+
+	query := config.DB.Model(&models.Playlist{}).Where("playlists.owner_id = ?", targetUserID)
+	if uint(targetUserID) != userID {
+		query = query.Where("playlists.visibility = ?", models.PlaylistVisibilityPublic)
+	}
+
+	var playlists []models.Playlist
+	if err := pagination.Apply(query.Preload("Owner")).Find(&playlists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlists"})
+		return
+	}
+
+	playlists, nextCursor := finishPage(pagination, playlists, func(p models.Playlist) []interface{} {
+		return []interface{}{p.CreatedAt, p.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"playlists":   playlists,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+// lockPlaylist serialises item changes on a playlist so positions stay
+// contiguous under concurrent edits.
+func lockPlaylist(tx *gorm.DB, playlistID uint) error {
+	var locked models.Playlist
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, playlistID).Error
+}
+
+func AddPlaylistItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, false)
+	if !ok {
+		return
+	}
+
+	var req PlaylistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.First(&room, req.RoomID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	if room.IsHidden || (room.IsPrivate && room.HostID != userID) || !room.IsVisibleTo(userID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This room can't be added to playlists"})
+		return
+	}
+
+	item := models.PlaylistItem{
+		PlaylistID: playlist.ID,
+		RoomID:     room.ID,
+		AddedByID:  userID,
+	}
+	created := false
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlist.ID); err != nil {
+			return err
+		}
+
+		var existing models.PlaylistItem
+		if err := tx.Where("playlist_id = ? AND room_id = ?", playlist.ID, room.ID).First(&existing).Error; err == nil {
+			item = existing
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.PlaylistItem{}).Where("playlist_id = ?", playlist.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= models.MaxPlaylistItems {
+			return errPlaylistFull
+		}
+
+		item.Position = int(count)
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		created = true
+
+		return tx.Model(&models.Playlist{}).Where("id = ?", playlist.ID).
+			Update("items_count", gorm.Expr("items_count + ?", 1)).Error
+	})
+	if errors.Is(err, errPlaylistFull) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Playlists can hold at most %d rooms", models.MaxPlaylistItems)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add room to playlist"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"success": true,
+		"item":    item,
+	})
+}
+
+// RemovePlaylistItem removes a room. The owner can remove anything;
+// collaborators only the rooms they added.
+func RemovePlaylistItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, false)
+	if !ok {
+		return
+	}
+
+	var item models.PlaylistItem
+	if err := config.DB.Where("id = ? AND playlist_id = ?", c.Param("itemId"), playlist.ID).First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist item not found"})
+		return
+	}
+
+	if playlist.OwnerID != userID && item.AddedByID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only remove rooms you added"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlist.ID); err != nil {
+			return err
+		}
+
+		result := tx.Delete(&item)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.PlaylistItem{}).
+			Where("playlist_id = ? AND position > ?", playlist.ID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Playlist{}).Where("id = ? AND items_count > 0", playlist.ID).
+			Update("items_count", gorm.Expr("items_count - ?", 1)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove room from playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Room removed from playlist",
+	})
+}
+
+// MovePlaylistItem moves an item to a zero-based position, shifting the items
+// in between. Positions past the end move the item to the end.
+func MovePlaylistItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, false)
+	if !ok {
+		return
+	}
+
+	var req MovePlaylistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	found := false
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPlaylist(tx, playlist.ID); err != nil {
+			return err
+		}
+
+		var items []models.PlaylistItem
+		if err := tx.Select("id", "position").
+			Where("playlist_id = ?", playlist.ID).
+			Order("position ASC, id ASC").
+			Find(&items).Error; err != nil {
+			return err
+		}
+
+		ordered := make([]uint, 0, len(items))
+		for _, item := range items {
+			if item.ID == uint(itemID) {
+				found = true
+				continue
+			}
+			ordered = append(ordered, item.ID)
+		}
+		if !found {
+			return nil
+		}
+
+		target := *req.Position
+		if target > len(ordered) {
+			target = len(ordered)
+		}
+		ordered = append(ordered[:target], append([]uint{uint(itemID)}, ordered[target:]...)...)
+
+		for i, id := range ordered {
+			if items[i].ID == id && items[i].Position == i {
+				continue
+			}
+			if err := tx.Model(&models.PlaylistItem{}).Where("id = ?", id).Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move playlist item"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist item not found"})
+		return
+	}
+
+	items, err := playableItems(playlist.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlist items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"items":   items,
+	})
+}
+
+func AddPlaylistCollaborator(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, true)
+	if !ok {
+		return
+	}
+
+	collaboratorID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if uint(collaboratorID) == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You already own this playlist"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.Where("id = ? AND is_active = ?", collaboratorID, true).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	collaborator := models.PlaylistCollaborator{PlaylistID: playlist.ID, UserID: user.ID}
+	if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&collaborator).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add collaborator"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Collaborator added",
+	})
+}
+
+// RemovePlaylistCollaborator lets the owner remove a collaborator, or a
+// collaborator leave the playlist.
+func RemovePlaylistCollaborator(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findPlaylist(c)
+	if !ok {
+		return
+	}
+
+	collaboratorID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if playlist.OwnerID != userID && uint(collaboratorID) != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found or unauthorized"})
+		return
+	}
+
+	if err := config.DB.Where("playlist_id = ? AND user_id = ?", playlist.ID, collaboratorID).
+		Delete(&models.PlaylistCollaborator{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove collaborator"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Collaborator removed",
+	})
+}
+
+// CreatePlaylistShareLink issues a new share token, invalidating any
+// previous link.
+func CreatePlaylistShareLink(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, true)
+	if !ok {
+		return
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := config.DB.Model(playlist).Update("share_token", token).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"share_token": token,
+		"share_path":  "/api/v1/playlists/shared/" + token,
+	})
+}
+
+func RevokePlaylistShareLink(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findEditablePlaylist(c, userID, true)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Model(playlist).Update("share_token", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Share link revoked",
+	})
+}
+
+func FollowPlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlist, ok := findPlaylist(c)
+	if !ok {
+		return
+	}
+
+	if playlist.OwnerID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't follow your own playlist"})
+		return
+	}
+
+	canView, _, err := playlistAccess(playlist, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !canView || playlist.Visibility != models.PlaylistVisibilityPublic {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		follow := models.PlaylistFollow{PlaylistID: playlist.ID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Playlist{}).Where("id = ?", playlist.ID).
+			Update("followers_count", gorm.Expr("followers_count + ?", 1)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"is_following": true,
+	})
+}
+
+func UnfollowPlaylist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	playlistID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("playlist_id = ? AND user_id = ?", playlistID, userID).Delete(&models.PlaylistFollow{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Playlist{}).Where("id = ? AND followers_count > 0", playlistID).
+			Update("followers_count", gorm.Expr("followers_count - ?", 1)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"is_following": false,
+	})
+}
+
+// GetFollowedPlaylists lists public playlists the caller follows. Playlists
+// that went private or whose owner hid the caller drop out.
+func GetFollowedPlaylists(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c,
+		SortKey{Column: "playlist_follows.created_at", Desc: true},
+		SortKey{Column: "playlist_follows.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiddenByUserIDs, err := GetUsersWhoHidMe(config.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden users"})
+		return
+	}
+
+	query := config.DB.Model(&models.PlaylistFollow{}).
+		Select("playlist_follows.*").
+		Joins("JOIN playlists ON playlists.id = playlist_follows.playlist_id AND playlists.deleted_at IS NULL").
+		Where("playlist_follows.user_id = ?", userID).
+		Where("playlists.visibility = ?", models.PlaylistVisibilityPublic)
+
+	if len(hiddenByUserIDs) > 0 {
+		query = query.Where("playlists.owner_id NOT IN ?", hiddenByUserIDs)
+	}
+
+	var follows []models.PlaylistFollow
+	if err := pagination.Apply(query).Find(&follows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlists"})
+		return
+	}
+
+	follows, nextCursor := finishPage(pagination, follows, func(f models.PlaylistFollow) []interface{} {
+		return []interface{}{f.CreatedAt, f.ID}
+	})
+
+	playlists := make([]models.Playlist, 0, len(follows))
+	if len(follows) > 0 {
+		ids := make([]uint, 0, len(follows))
+		for _, f := range follows {
+			ids = append(ids, f.PlaylistID)
+		}
+
+		var found []models.Playlist
+		if err := config.DB.Preload("Owner").Where("id IN ?", ids).Find(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch playlists"})
+			return
+		}
+		byID := make(map[uint]models.Playlist, len(found))
+		for _, p := range found {
+			byID[p.ID] = p
+		}
+		for _, id := range ids {
+			if p, ok := byID[id]; ok {
+				playlists = append(playlists, p)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"playlists":   playlists,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
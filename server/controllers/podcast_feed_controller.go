@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PodcastFeedSettingsRequest struct {
+	Enabled  *bool `json:"enabled"`
+	Explicit *bool `json:"explicit"`
+}
+
+// publicBaseURL is the origin used for absolute links in feeds. PUBLIC_BASE_URL
+// overrides it when the server sits behind a proxy that rewrites Host.
+func publicBaseURL(c *gin.Context) string {
+	if base := os.Getenv("PUBLIC_BASE_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	return fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+}
+
+func podcastFeedURL(c *gin.Context, userID uint) string {
+	return fmt.Sprintf("%s/api/v1/users/%d/podcast.rss", publicBaseURL(c), userID)
+}
+
+func GetPodcastFeedSettings(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  user.PodcastFeedEnabled,
+		"explicit": user.PodcastFeedExplicit,
+		"feed_url": podcastFeedURL(c, user.ID),
+	})
+}
+
+// UpdatePodcastFeedSettings turns the caller's public RSS feed on or off.
+func UpdatePodcastFeedSettings(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req PodcastFeedSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if req.Enabled != nil {
+		user.PodcastFeedEnabled = *req.Enabled
+	}
+	if req.Explicit != nil {
+		user.PodcastFeedExplicit = *req.Explicit
+	}
+
+	if err := config.DB.Model(&user).
+		Select("podcast_feed_enabled", "podcast_feed_explicit").
+		Updates(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update podcast feed settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  user.PodcastFeedEnabled,
+		"explicit": user.PodcastFeedExplicit,
+		"feed_url": podcastFeedURL(c, user.ID),
+	})
+}
+
+// GetPodcastFeed serves a host's public rooms as an RSS podcast feed. The
+// feed is only available once the host opts in. Responses carry an ETag and
+// Last-Modified so podcast apps can poll with conditional requests.
+func GetPodcastFeed(c *gin.Context) {
+	hostID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.String(http.StatusNotFound, "feed not found")
+		return
+	}
+
+	var host models.User
+	if err := config.DB.Where("id = ? AND is_active = ? AND podcast_feed_enabled = ?", hostID, true, true).
+		First(&host).Error; err != nil {
+		c.String(http.StatusNotFound, "feed not found")
+		return
+	}
+
+	publishable := config.DB.Model(&models.Room{}).
+		Where("host_id = ?", host.ID).
+		Where("is_private = ? AND is_hidden = ? AND status = ?", false, false, models.RoomStatusPublished).
+		Where("audio_url <> ''").
+		Session(&gorm.Session{})
+
+	var rooms []models.Room
+	if err := publishable.
+		Order("created_at DESC").
+		Limit(services.MaxPodcastFeedItems).
+		Find(&rooms).Error; err != nil {
+		c.String(http.StatusInternalServerError, "failed to load feed")
+		return
+	}
+
+	var topTopics []string
+	publishable.
+		Group("topic").
+		Order("COUNT(*) DESC").
+		Limit(1).
+		Pluck("topic", &topTopics)
+	topTopic := ""
+	if len(topTopics) > 0 {
+		topTopic = topTopics[0]
+	}
+
+	feed := services.PodcastFeed{
+		Host:     host,
+		Rooms:    rooms,
+		FeedURL:  podcastFeedURL(c, host.ID),
+		SiteURL:  publicBaseURL(c),
+		Explicit: host.PodcastFeedExplicit,
+		Category: services.ITunesCategoryForTopic(topTopic),
+	}
+
+	body, err := feed.Render()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to render feed")
+		return
+	}
+
+	// Validators come from the rendered document, so plays, counters and
+	// other row updates that don't show in the feed never invalidate it.
+	sum := sha1.Sum(body)
+	etag := `"` + hex.EncodeToString(sum[:10]) + `"`
+
+	lastModified := time.Now().UTC().Truncate(time.Second)
+	if host.PodcastFeedETag == etag && host.PodcastFeedModifiedAt != nil {
+		lastModified = host.PodcastFeedModifiedAt.UTC()
+	} else {
+		config.DB.Model(&host).UpdateColumns(map[string]interface{}{
+			"podcast_feed_etag":        etag,
+			"podcast_feed_modified_at": lastModified,
+		})
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=900")
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if etagMatches(match, etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil && !lastModified.After(t) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", body)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
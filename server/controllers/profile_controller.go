@@ -105,6 +105,7 @@ func UpdateUserProfile(c *gin.Context) {
 		FullName   string `json:"full_name"`
 		Bio        *string `json:"bio"`
 		ProfilePic string `json:"profile_pic"`
+		AllowMentions *bool `json:"allow_mentions"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -167,6 +168,10 @@ func UpdateUserProfile(c *gin.Context) {
 	
 	user.ProfilePic = input.ProfilePic
 
+	if input.AllowMentions != nil {
+		user.AllowMentions = *input.AllowMentions
+	}
+
 	if err := config.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to update profile",
@@ -178,12 +183,13 @@ func UpdateUserProfile(c *gin.Context) {
 		"success": true,
 		"message": "Profile updated successfully",
 		"user": gin.H{
-			"id":          user.ID,
-			"email":       user.Email,
-			"username":    user.Username,
-			"full_name":   user.FullName,
-			"profile_pic": user.ProfilePic,
-			"bio":         user.Bio,
+			"id":             user.ID,
+			"email":          user.Email,
+			"username":       user.Username,
+			"full_name":      user.FullName,
+			"profile_pic":    user.ProfilePic,
+			"bio":            user.Bio,
+			"allow_mentions": user.AllowMentions,
 		},
 	})
 }
\ No newline at end of file
@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
+	"strings"
 	"voxarena_server/config"
 	"voxarena_server/models"
+	"voxarena_server/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
 )
 
 type QueueRequest struct {
@@ -60,7 +64,21 @@ func GetSmartQueue(c *gin.Context) {
 			Pluck("topic", &listenedTopics)
 	}
 
+	followedTopics, err := models.GetFollowedTopicNames(db, userID)
+	if err != nil {
+		log.Printf("Failed to load followed topics for user %d: %v", userID, err)
+	}
+
 	var queue []models.Room
+	hostCounts := map[uint]int{}
+
+	// The next episode of a series always plays first.
+	skipIDs := append([]uint{req.CurrentRoomID}, listenedRoomIDs...)
+	if next := nextSeriesEpisode(req.CurrentRoomID, userID, hiddenByUserIDs, reportedRoomIDs); next != nil {
+		queue = append(queue, *next)
+		hostCounts[next.HostID]++
+		skipIDs = append(skipIDs, next.ID)
+	}
 
 	if req.Topic != "" {
 		var topicRooms []models.Room
@@ -69,8 +87,8 @@ func GetSmartQueue(c *gin.Context) {
 			Where("topic = ?", req.Topic).
 			Where("id != ?", req.CurrentRoomID).
 			Where("is_private = ?", false).
-			Where("is_hidden = ?", false).
-			Where("id NOT IN ?", append(listenedRoomIDs, req.CurrentRoomID))
+			Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished).
+			Where("id NOT IN ?", skipIDs)
 
 		if len(hiddenByUserIDs) > 0 {
 			topicQuery = topicQuery.Where("host_id NOT IN ?", hiddenByUserIDs)
@@ -81,10 +99,28 @@ func GetSmartQueue(c *gin.Context) {
 		}
 
 		topicQuery.Order("likes_count DESC, total_listens DESC").
-			Limit(req.Limit / 2).
+			Limit(req.Limit * 3 / 2).
 			Find(&topicRooms)
 
-		queue = append(queue, topicRooms...)
+		queue = appendDiverse(queue, topicRooms, hostCounts, len(queue)+req.Limit/2)
+	}
+
+	// Personal recommendations from the item-item similarity table come
+	// first; topic overlap below covers users and rooms without enough history.
+	if remainingSlots := req.Limit - len(queue); remainingSlots > 0 {
+		excludeIDs := []uint{req.CurrentRoomID}
+		for _, room := range queue {
+			excludeIDs = append(excludeIDs, room.ID)
+		}
+		excludeIDs = append(excludeIDs, listenedRoomIDs...)
+		excludeIDs = append(excludeIDs, reportedRoomIDs...)
+
+		personalRooms, err := services.NewRecommendationService(db).
+			RecommendRooms(userID, req.CurrentRoomID, excludeIDs, hiddenByUserIDs, hostCounts, remainingSlots)
+		if err != nil {
+			log.Printf("Failed to load personal recommendations for user %d: %v", userID, err)
+		}
+		queue = append(queue, personalRooms...)
 	}
 
 	remainingSlots := req.Limit - len(queue)
@@ -101,7 +137,7 @@ func GetSmartQueue(c *gin.Context) {
 			Preload("Host").
 			Where("id NOT IN ?", excludeIDs).
 			Where("is_private = ?", false).
-			Where("is_hidden = ?", false)
+			Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished)
 
 		if len(hiddenByUserIDs) > 0 {
 			recQuery = recQuery.Where("host_id NOT IN ?", hiddenByUserIDs)
@@ -111,15 +147,20 @@ func GetSmartQueue(c *gin.Context) {
 			recQuery = recQuery.Where("id NOT IN ?", reportedRoomIDs)
 		}
 
-		if len(listenedTopics) > 0 {
-			recQuery = recQuery.Where("topic IN ?", listenedTopics)
+		// Followed topics widen the topic filter and rank ahead of topics the
+		// user merely listened to.
+		if signalTopics := append(listenedTopics, followedTopics...); len(signalTopics) > 0 {
+			recQuery = recQuery.Where("topic IN ?", signalTopics)
+		}
+		if len(followedTopics) > 0 {
+			recQuery = recQuery.Order(clause.OrderBy{Expression: clause.Expr{SQL: "topic IN ? DESC", Vars: []interface{}{followedTopics}}})
 		}
 
 		recQuery.Order("likes_count DESC, total_listens DESC").
-			Limit(remainingSlots).
+			Limit(remainingSlots * 3).
 			Find(&recommendedRooms)
 
-		queue = append(queue, recommendedRooms...)
+		queue = appendDiverse(queue, recommendedRooms, hostCounts, req.Limit)
 	}
 
 	if len(queue) < req.Limit {
@@ -135,7 +176,7 @@ func GetSmartQueue(c *gin.Context) {
 			Preload("Host").
 			Where("id NOT IN ?", excludeIDs).
 			Where("is_private = ?", false).
-			Where("is_hidden = ?", false)
+			Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished)
 
 		if len(hiddenByUserIDs) > 0 {
 			popularQuery = popularQuery.Where("host_id NOT IN ?", hiddenByUserIDs)
@@ -146,10 +187,10 @@ func GetSmartQueue(c *gin.Context) {
 		}
 
 		popularQuery.Order("likes_count DESC, total_listens DESC").
-			Limit(remainingSlots).
+			Limit(remainingSlots * 3).
 			Find(&popularRooms)
 
-		queue = append(queue, popularRooms...)
+		queue = appendDiverse(queue, popularRooms, hostCounts, req.Limit)
 	}
 
 	c.JSON(http.StatusOK, QueueResponse{
@@ -158,6 +199,54 @@ func GetSmartQueue(c *gin.Context) {
 	})
 }
 
+// nextSeriesEpisode returns the episode after roomID in its series, if any,
+// skipping episodes the user can't see.
+func nextSeriesEpisode(roomID, userID uint, hiddenByUserIDs, reportedRoomIDs []uint) *models.Room {
+	var current models.Room
+	if err := config.DB.Select("id, series_id, season_number, episode_number").First(&current, roomID).Error; err != nil {
+		return nil
+	}
+	if current.SeriesID == nil || current.SeasonNumber == nil || current.EpisodeNumber == nil {
+		return nil
+	}
+
+	query := config.DB.Model(&models.Room{}).
+		Preload("Host").
+		Where("series_id = ?", *current.SeriesID).
+		Where("(season_number, episode_number, id) > (?, ?, ?)", *current.SeasonNumber, *current.EpisodeNumber, current.ID).
+		Where("is_private = ? OR host_id = ?", false, userID).
+		Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished)
+
+	if len(hiddenByUserIDs) > 0 {
+		query = query.Where("host_id NOT IN ?", hiddenByUserIDs)
+	}
+	if len(reportedRoomIDs) > 0 {
+		query = query.Where("id NOT IN ?", reportedRoomIDs)
+	}
+
+	var next models.Room
+	if err := query.Order("season_number ASC, episode_number ASC, id ASC").First(&next).Error; err != nil {
+		return nil
+	}
+	return &next
+}
+
+// appendDiverse appends rooms to queue until it holds limit rooms, skipping
+// hosts that already have services.MaxQueueRoomsPerHost rooms in the queue.
+func appendDiverse(queue []models.Room, rooms []models.Room, hostCounts map[uint]int, limit int) []models.Room {
+	for _, room := range rooms {
+		if len(queue) >= limit {
+			break
+		}
+		if hostCounts[room.HostID] >= services.MaxQueueRoomsPerHost {
+			continue
+		}
+		hostCounts[room.HostID]++
+		queue = append(queue, room)
+	}
+	return queue
+}
+
 func GetQueueFromSearch(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	currentRoomID := c.Query("current_room_id")
@@ -169,6 +258,15 @@ func GetQueueFromSearch(c *gin.Context) {
 		return
 	}
 
+	filters, err := parseRoomSearchFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filters",
+			"message": err.Error(),
+		})
+		return
+	}
+
 	db := config.DB
 
 	hiddenByUserIDs, err := GetUsersWhoHidMe(db, userID)
@@ -183,15 +281,26 @@ func GetQueueFromSearch(c *gin.Context) {
 		Pluck("room_id", &reportedRoomIDs)
 
 	var queue []models.Room
-	searchPattern := "%" + searchQuery + "%"
 
 	query := db.Model(&models.Room{}).
 		Preload("Host").
-		Where("(title ILIKE ? OR description ILIKE ? OR topic ILIKE ?)",
-			searchPattern, searchPattern, searchPattern).
 		Where("id != ?", currentRoomID).
 		Where("is_private = ?", false).
-		Where("is_hidden = ?", false)
+		Where("is_hidden = ? AND status = ?", false, models.RoomStatusPublished)
+
+	relevance := ""
+	textParams := map[string]interface{}{
+		"tsquery": services.BuildPrefixTSQuery(searchQuery),
+		"query":   searchQuery,
+	}
+	if strings.TrimSpace(searchQuery) != "" {
+		query = query.Where("(rooms.search_vector @@ to_tsquery('english', @tsquery) OR rooms.title % @query)", textParams)
+		relevance = "ts_rank_cd(rooms.search_vector, to_tsquery('english', @tsquery), 32) + similarity(rooms.title, @query)"
+	}
+
+	if filterSQL, params := filters.Conditions(userID); filterSQL != "" {
+		query = query.Where(filterSQL, params)
+	}
 
 	if len(hiddenByUserIDs) > 0 {
 		query = query.Where("host_id NOT IN ?", hiddenByUserIDs)
@@ -212,7 +321,10 @@ func GetQueueFromSearch(c *gin.Context) {
 		query = query.Where("id NOT IN ?", listenedRoomIDs)
 	}
 
-	query.Order("likes_count DESC, total_listens DESC").
+	query.Clauses(clause.OrderBy{Expression: clause.NamedExpr{
+		SQL:  filters.OrderBy(relevance),
+		Vars: []interface{}{textParams},
+	}}).
 		Limit(limit).
 		Find(&queue)
 
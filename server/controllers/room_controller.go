@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -44,6 +45,43 @@ func CreateRoom(c *gin.Context) {
 		return
 	}
 
+	roomTopic, err := models.FindActiveTopic(config.DB, topic)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	var series *models.Series
+	if seriesID := c.PostForm("series_id"); seriesID != "" {
+		var found models.Series
+		if err := config.DB.Where("id = ? AND host_id = ?", seriesID, userID).First(&found).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series"})
+			return
+		}
+		series = &found
+	}
+
+	status, publishAt, err := parseRoomStatus(c.PostForm("status"), c.PostForm("publish_at"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seasonNumber, err := parseEpisodeNumber(c.PostForm("season_number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Season number " + err.Error()})
+		return
+	}
+	episodeNumber, err := parseEpisodeNumber(c.PostForm("episode_number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Episode number " + err.Error()})
+		return
+	}
+
 	audioFile, audioHeader, err := c.Request.FormFile("audio_file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
@@ -117,7 +155,8 @@ func CreateRoom(c *gin.Context) {
 	room := models.Room{
 		Title:         strings.TrimSpace(title),
 		Description:   strings.TrimSpace(description),
-		Topic:         topic,
+		Topic:         roomTopic.Name,
+		TopicID:       &roomTopic.ID,
 		AudioURL:      audioURL,
 		ThumbnailURL:  thumbnailURL,
 		Duration:      duration,
@@ -125,21 +164,43 @@ func CreateRoom(c *gin.Context) {
 		IsLive:        false,
 		IsPrivate:     isPrivate,
 		ListenerCount: 0,
+		Status:        status,
+		PublishAt:     publishAt,
 	}
 
-	if err := config.DB.Create(&room).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if series != nil {
+			season, episode, err := resolveEpisodeSlot(tx, series.ID, 0, seasonNumber, episodeNumber)
+			if err != nil {
+				return err
+			}
+			room.SeriesID = &series.ID
+			room.SeasonNumber = &season
+			room.EpisodeNumber = &episode
+		}
+		if err := tx.Create(&room).Error; err != nil {
+			return err
+		}
+		return models.SyncRoomHashtags(tx, room.ID, room.Description)
+	}); err != nil {
+		if errors.Is(err, errEpisodeSlotTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Another room already has this season and episode number"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create room"})
 		return
 	}
 
 	config.DB.Preload("Host").First(&room, room.ID)
 
-	notificationService := services.NewNotificationService(config.DB)
-go func() {
-	if err := notificationService.NotifyNewRoom(&room); err != nil {
-		log.Printf("⚠️ Failed to send notifications: %v", err)
+	if room.Status == models.RoomStatusPublished {
+		notificationService := services.NewNotificationService(config.DB)
+		go func() {
+			if err := notificationService.NotifyNewRoom(&room); err != nil {
+				log.Printf("⚠️ Failed to send notifications: %v", err)
+			}
+		}()
 	}
-}()
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
@@ -157,6 +218,8 @@ go func() {
 			"host_avatar":    room.Host.ProfilePic,
 			"listener_count": room.ListenerCount,
 			"is_live":        room.IsLive,
+			"status":         room.Status,
+			"publish_at":     room.PublishAt,
 			"created_at":     room.CreatedAt,
 		},
 	})
@@ -194,7 +257,7 @@ func GetRooms(c *gin.Context) {
 		}
 	}
 
-	query := db.Preload("Host").Order("created_at DESC")
+	query := db.Preload("Host").Where("status = ?", models.RoomStatusPublished)
 
 	if len(blockedBy) > 0 {
 		query = query.Where("host_id NOT IN ?", blockedBy)
@@ -210,10 +273,13 @@ func GetRooms(c *gin.Context) {
 		query = query.Where("is_live = ?", true)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
-	countQuery := db.Model(&models.Room{})
+	pagination, err := parsePagination(c, createdAtKeys("rooms")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	countQuery := db.Model(&models.Room{}).Where("status = ?", models.RoomStatusPublished)
 
 	if len(blockedBy) > 0 {
 		countQuery = countQuery.Where("host_id NOT IN ?", blockedBy)
@@ -230,16 +296,17 @@ func GetRooms(c *gin.Context) {
 	var total int64
 	countQuery.Count(&total)
 
-	if err := query.
-		Limit(limit).
-		Offset(offset).
-		Find(&rooms).Error; err != nil {
+	if err := pagination.Apply(query).Find(&rooms).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch rooms",
 		})
 		return
 	}
 
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
 	for i := range rooms {
 		if rooms[i].HostID > 0 {
 			var host models.User
@@ -250,12 +317,13 @@ func GetRooms(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"rooms":    rooms,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(rooms) < int(total),
+		"success":     true,
+		"rooms":       rooms,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -267,6 +335,10 @@ func GetRoomByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
+	if !room.IsVisibleTo(c.GetUint("user_id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -281,44 +353,43 @@ func GetMyRooms(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("rooms")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	db := config.DB
 	var rooms []models.Room
 	var total int64
 
-	countQuery := db.Model(&models.Room{}).
+	query := db.Model(&models.Room{}).
 		Where("host_id = ?", userID)
 
-	countQuery.Count(&total)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Session(&gorm.Session{}).Count(&total)
 
-	if err := db.
-		Preload("Host").
-		Where("host_id = ?", userID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+	if err := pagination.Apply(query.Preload("Host")).
 		Find(&rooms).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rooms"})
 		return
 	}
 
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"rooms":    rooms,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(rooms) < int(total),
+		"success":     true,
+		"rooms":       rooms,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -386,9 +457,31 @@ func UpdateRoom(c *gin.Context) {
 		return
 	}
 
+	// Keeping the room's current topic is allowed even after that topic was
+	// deactivated; only a change of topic needs an active one.
+	var roomTopic *models.Topic
+	if room.TopicID != nil {
+		var current models.Topic
+		if err := config.DB.First(&current, *room.TopicID).Error; err == nil && current.Matches(topic) {
+			roomTopic = &current
+		}
+	}
+	if roomTopic == nil {
+		roomTopic, err = models.FindActiveTopic(config.DB, topic)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			}
+			return
+		}
+	}
+
 	room.Title = title
 	room.Description = description
-	room.Topic = topic
+	room.Topic = roomTopic.Name
+	room.TopicID = &roomTopic.ID
 
 	fileHeader, err := c.FormFile("thumbnail")
 	if err == nil && fileHeader != nil {
@@ -417,7 +510,12 @@ func UpdateRoom(c *gin.Context) {
 		room.ThumbnailURL = thumbURL
 	}
 
-	if err := config.DB.Save(&room).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&room).Error; err != nil {
+			return err
+		}
+		return models.SyncRoomHashtags(tx, room.ID, room.Description)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update room"})
 		return
 	}
@@ -593,17 +691,12 @@ func GetUserRooms(c *gin.Context) {
 		currentUserID = uint(v)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 20
+	pagination, err := parsePagination(c, createdAtKeys("rooms")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-
 	db := config.DB
 	var rooms []models.Room
 	var total int64
@@ -618,12 +711,13 @@ func GetUserRooms(c *gin.Context) {
 
 		if err == nil {
 			c.JSON(http.StatusOK, gin.H{
-				"success":  true,
-				"rooms":    []models.Room{},
-				"page":     page,
-				"limit":    limit,
-				"total":    0,
-				"has_more": false,
+				"success":     true,
+				"rooms":       []models.Room{},
+				"page":        pagination.Page,
+				"limit":       pagination.Limit,
+				"total":       0,
+				"has_more":    false,
+				"next_cursor": nil,
 			})
 			return
 		}
@@ -638,29 +732,30 @@ func GetUserRooms(c *gin.Context) {
 
 	if fmt.Sprint(currentUserID) != targetUserID {
 		query = query.Where("is_hidden = ?", false)
+		query = query.Where("status = ?", models.RoomStatusPublished)
 	}
 
 	query.Count(&total)
 
-	if err := query.
-		Preload("Host").
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&rooms).Error; err != nil {
+	if err := pagination.Apply(query.Preload("Host")).Find(&rooms).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch rooms",
 		})
 		return
 	}
 
+	rooms, nextCursor := finishPage(pagination, rooms, func(r models.Room) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"rooms":    rooms,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(rooms) < int(total),
+		"success":     true,
+		"rooms":       rooms,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -675,38 +770,17 @@ func RecordUniqueListenIfNew(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
 	var room models.Room
-	if err := config.DB.First(&room, roomID).Error; err != nil {
+	if err := config.DB.First(&room, roomID).Error; err != nil || !room.IsVisibleTo(userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
 
-	var existingListen models.UniqueRoomListen
-	err = config.DB.Where("room_id = ? AND user_id = ?", roomID, userID).
-		First(&existingListen).Error
-
-	isNewListener := err == gorm.ErrRecordNotFound
-
-	if isNewListener {
-		uniqueListen := models.UniqueRoomListen{
-			RoomID: uint(roomID),
-			UserID: userID,
-		}
-
-		if err := config.DB.Create(&uniqueListen).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to record unique listen",
-			})
-			return
-		}
-
-		if err := config.DB.Model(&room).
-			Update("total_listens", gorm.Expr("total_listens + ?", 1)).
-			Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to increment listen count",
-			})
-			return
-		}
+	isNewListener, err := models.RecordUniqueListen(config.DB, userID, uint(roomID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to record unique listen",
+		})
+		return
 	}
 
 	config.DB.First(&room, roomID)
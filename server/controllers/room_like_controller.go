@@ -7,68 +7,91 @@ import (
 	"voxarena_server/models"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 func ToggleLike(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	userID, roomID, ok := parseRoomLikeRequest(c)
+	if !ok {
 		return
 	}
 
-	roomIDStr := c.Param("id")
+	var like models.RoomLike
+	isLiked := config.DB.Where("user_id = ? AND room_id = ?", userID, roomID).First(&like).Error == nil
+
+	setRoomLike(c, userID, roomID, !isLiked)
+}
 
-	var roomID uint
-	if _, err := fmt.Sscanf(roomIDStr, "%d", &roomID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+func LikeRoom(c *gin.Context) {
+	userID, roomID, ok := parseRoomLikeRequest(c)
+	if !ok {
 		return
 	}
 
-	var room models.Room
-	if err := config.DB.First(&room, roomID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+	setRoomLike(c, userID, roomID, true)
+}
+
+func UnlikeRoom(c *gin.Context) {
+	userID, roomID, ok := parseRoomLikeRequest(c)
+	if !ok {
 		return
 	}
 
-	var existingLike models.RoomLike
-	err := config.DB.Where("user_id = ? AND room_id = ?", userID, roomID).First(&existingLike).Error
+	setRoomLike(c, userID, roomID, false)
+}
 
-	if err == nil {
-		if err := config.DB.Delete(&existingLike).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike"})
-			return
-		}
+func parseRoomLikeRequest(c *gin.Context) (uint, uint, bool) {
+	userID := c.GetUint("user_id")
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, 0, false
+	}
 
-		config.DB.Model(&room).Update("likes_count", gorm.Expr("GREATEST(likes_count - 1, 0)"))
-		config.DB.First(&room, roomID)
+	var roomID uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &roomID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return 0, 0, false
+	}
 
-		c.JSON(http.StatusOK, gin.H{
-			"success":     true,
-			"action":      "unliked",
-			"is_liked":    false,
-			"likes_count": room.LikesCount,
-		})
-		return
+	var room models.Room
+	if err := config.DB.Select("id, host_id, status").First(&room, roomID).Error; err != nil || !room.IsVisibleTo(userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return 0, 0, false
 	}
 
-	newLike := models.RoomLike{
-		UserID: userID.(uint),
-		RoomID: roomID, 
+	return userID, roomID, true
+}
+
+// setRoomLike moves the like into the requested state. Repeating the same
+// request is a no-op, so clients can safely retry it.
+func setRoomLike(c *gin.Context, userID, roomID uint, liked bool) {
+	var err error
+	if liked {
+		_, err = models.AddRoomLike(config.DB, userID, roomID)
+	} else {
+		_, err = models.RemoveRoomLike(config.DB, userID, roomID)
 	}
 
-	if err := config.DB.Create(&newLike).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like"})
+	if err != nil {
+		if liked {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike"})
+		}
 		return
 	}
 
-	config.DB.Model(&room).Update("likes_count", gorm.Expr("likes_count + 1"))
-	config.DB.First(&room, roomID)
+	var room models.Room
+	config.DB.Select("id", "likes_count").First(&room, roomID)
+
+	action := "unliked"
+	if liked {
+		action = "liked"
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
-		"action":      "liked",
-		"is_liked":    true,
+		"action":      action,
+		"is_liked":    liked,
 		"likes_count": room.LikesCount,
 	})
 }
@@ -98,4 +121,4 @@ func CheckIfLiked(c *gin.Context) {
 		"is_liked":    isLiked,
 		"likes_count": room.LikesCount,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RoomStatusRequest struct {
+	Status    string `json:"status"`
+	PublishAt string `json:"publish_at"`
+}
+
+// parseRoomStatus validates a requested status and publish time. A publish
+// time on its own implies scheduled; neither means publish now.
+func parseRoomStatus(status, publishAt string) (string, *time.Time, error) {
+	status = strings.TrimSpace(status)
+	publishAt = strings.TrimSpace(publishAt)
+
+	if status == "" {
+		status = models.RoomStatusPublished
+		if publishAt != "" {
+			status = models.RoomStatusScheduled
+		}
+	}
+
+	switch status {
+	case models.RoomStatusDraft, models.RoomStatusPublished:
+		return status, nil, nil
+	case models.RoomStatusScheduled:
+		if publishAt == "" {
+			return "", nil, errors.New("publish_at is required for scheduled rooms")
+		}
+		at, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return "", nil, errors.New("publish_at must be an RFC 3339 timestamp")
+		}
+		if !at.After(time.Now()) {
+			return "", nil, errors.New("publish_at must be in the future")
+		}
+		return status, &at, nil
+	default:
+		return "", nil, errors.New("status must be draft, scheduled or published")
+	}
+}
+
+// UpdateRoomStatus moves one of the host's unpublished rooms between draft
+// and scheduled, or publishes it now. Published rooms can't go back to
+// draft since followers have already been notified.
+func UpdateRoomStatus(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req RoomStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if strings.TrimSpace(req.Status) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status is required"})
+		return
+	}
+
+	status, publishAt, err := parseRoomStatus(req.Status, req.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var room models.Room
+	if err := config.DB.Where("id = ? AND host_id = ?", c.Param("id"), userID).First(&room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if room.Status == models.RoomStatusPublished {
+		if status != models.RoomStatusPublished {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Room is already published"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "room": room})
+		return
+	}
+
+	if status == models.RoomStatusPublished {
+		if _, err := services.NewRoomPublishService(config.DB).Publish(room.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish room"})
+			return
+		}
+	} else {
+		result := config.DB.Model(&models.Room{}).
+			Where("id = ? AND status <> ?", room.ID, models.RoomStatusPublished).
+			Updates(map[string]interface{}{"status": status, "publish_at": publishAt})
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update room status"})
+			return
+		}
+		if result.RowsAffected == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Room was published in the meantime"})
+			return
+		}
+	}
+
+	config.DB.Preload("Host").First(&room, room.ID)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "room": room})
+}
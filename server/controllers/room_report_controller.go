@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const REPORT_THRESHOLD = 10
@@ -64,25 +65,17 @@ func ReportRoom(c *gin.Context) {
 		return
 	}
 
-	if room.IsHidden {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This room has already been hidden due to reports"})
+	if !room.IsVisibleTo(userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
 
-	var existingReport models.RoomReport
-	err = db.Where("room_id = ? AND reporter_id = ?", roomID, userID).First(&existingReport).Error
-	if err == nil {
-		c.JSON(http.StatusConflict, gin.H{
-			"error":       "You have already reported this room",
-			"status":      existingReport.Status,
-			"reported_at": existingReport.CreatedAt,
-		})
-		return
-	} else if err != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	if room.IsHidden {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This room has already been hidden due to reports"})
 		return
 	}
 
+	alreadyReported := false
 	err = db.Transaction(func(tx *gorm.DB) error {
 		report := models.RoomReport{
 			RoomID:     uint(roomID),
@@ -92,8 +85,13 @@ func ReportRoom(c *gin.Context) {
 			Status:     "pending",
 		}
 
-		if err := tx.Create(&report).Error; err != nil {
-			return err
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			alreadyReported = true
+			return nil
 		}
 
 		if err := tx.Model(&models.Room{}).
@@ -133,9 +131,20 @@ func ReportRoom(c *gin.Context) {
 		return
 	}
 
+	if alreadyReported {
+		var existingReport models.RoomReport
+		db.Where("room_id = ? AND reporter_id = ?", roomID, userID).First(&existingReport)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "You have already reported this room",
+			"status":      existingReport.Status,
+			"reported_at": existingReport.CreatedAt,
+		})
+		return
+	}
+
 	var message string
 	var roomHidden bool
-	if room.ReportCount+1 >= REPORT_THRESHOLD {
+	if room.ReportCount >= REPORT_THRESHOLD {
 		message = "Report submitted. Room has been hidden due to multiple reports."
 		roomHidden = true
 	} else {
@@ -146,7 +155,7 @@ func ReportRoom(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success":      true,
 		"message":      message,
-		"report_count": room.ReportCount + 1,
+		"report_count": room.ReportCount,
 		"room_hidden":  roomHidden,
 	})
 }
@@ -161,31 +170,35 @@ func GetRoomReports(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	pagination, err := parsePagination(c, createdAtKeys("room_reports")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var total int64
 	db.Model(&models.RoomReport{}).Where("room_id = ?", roomID).Count(&total)
 
 	var reports []models.RoomReport
-	if err := db.
+	if err := pagination.Apply(db.
 		Preload("Reporter").
-		Where("room_id = ?", roomID).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
+		Where("room_id = ?", roomID)).
 		Find(&reports).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
 		return
 	}
 
+	reports, nextCursor := finishPage(pagination, reports, func(r models.RoomReport) []interface{} {
+		return []interface{}{r.CreatedAt, r.ID}
+	})
+
 	c.JSON(http.StatusOK, gin.H{
-		"reports":  reports,
-		"page":     page,
-		"limit":    limit,
-		"total":    total,
-		"has_more": offset+len(reports) < int(total),
+		"reports":     reports,
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxSavedSearchesPerUser = 20
+
+// savedSearchFilterKeys are the filter parameters that can be stored with a
+// saved search. exclude_listened is left out on purpose: alerts only look at
+// rooms published since the last run.
+var savedSearchFilterKeys = []string{
+	"topics", "min_duration", "max_duration", "uploaded_after", "uploaded_before",
+	"verified_only", "min_likes", "sort",
+}
+
+type SavedSearchRequest struct {
+	Name          string            `json:"name"`
+	Query         string            `json:"query"`
+	Filters       map[string]string `json:"filters"`
+	Frequency     string            `json:"frequency"`
+	AlertsEnabled *bool             `json:"alerts_enabled"`
+}
+
+func GetSavedSearches(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var searches []models.SavedSearch
+	if err := db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved searches"})
+		return
+	}
+
+	results := make([]gin.H, 0, len(searches))
+	for _, search := range searches {
+		results = append(results, savedSearchResponse(search))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"saved_searches": results,
+	})
+}
+
+func CreateSavedSearch(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var count int64
+	db.Model(&models.SavedSearch{}).Where("user_id = ?", userID).Count(&count)
+	if count >= maxSavedSearchesPerUser {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You have reached the maximum number of saved searches"})
+		return
+	}
+
+	now := time.Now()
+	search := models.SavedSearch{
+		UserID:        userID,
+		Frequency:     models.SavedSearchFrequencyDaily,
+		AlertsEnabled: true,
+		LastRunAt:     now,
+	}
+	if req.Frequency == "" {
+		req.Frequency = models.SavedSearchFrequencyDaily
+	}
+	if req.Filters == nil {
+		req.Filters = map[string]string{}
+	}
+
+	if err := applySavedSearchRequest(&search, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	search.NextRunAt = now.Add(models.SavedSearchFrequencies[search.Frequency])
+
+	if err := db.Create(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"saved_search": savedSearchResponse(search),
+	})
+}
+
+func UpdateSavedSearch(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	search, ok := loadOwnSavedSearch(c, db, userID)
+	if !ok {
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	previousFrequency := search.Frequency
+	if err := applySavedSearchRequest(&search, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if search.Frequency != previousFrequency {
+		search.NextRunAt = search.LastRunAt.Add(models.SavedSearchFrequencies[search.Frequency])
+	}
+
+	if err := db.Save(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"saved_search": savedSearchResponse(search),
+	})
+}
+
+func DeleteSavedSearch(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	search, ok := loadOwnSavedSearch(c, db, userID)
+	if !ok {
+		return
+	}
+
+	if err := db.Delete(&search).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Saved search deleted",
+	})
+}
+
+// GetSavedSearchResults runs a saved search right now, without the
+// "since last run" window used by alerts.
+func GetSavedSearchResults(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	search, ok := loadOwnSavedSearch(c, db, userID)
+	if !ok {
+		return
+	}
+
+	filters, err := services.SavedSearchFilters(search)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rooms, err := services.SearchRooms(db, search.Query, userID, filters, services.SavedSearchMatchLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"saved_search": savedSearchResponse(search),
+		"rooms":        rooms,
+	})
+}
+
+func loadOwnSavedSearch(c *gin.Context, db *gorm.DB, userID uint) (models.SavedSearch, bool) {
+	var search models.SavedSearch
+
+	searchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return search, false
+	}
+
+	if err := db.Where("id = ? AND user_id = ?", searchID, userID).First(&search).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return search, false
+	}
+
+	return search, true
+}
+
+// applySavedSearchRequest validates req and copies the fields that were set
+// onto search.
+func applySavedSearchRequest(search *models.SavedSearch, req SavedSearchRequest) error {
+	if req.Query != "" || search.Query == "" {
+		query := strings.TrimSpace(req.Query)
+		if len(query) < 2 || len(query) > 100 {
+			return fmt.Errorf("Search query must be between 2 and 100 characters")
+		}
+		search.Query = query
+	}
+
+	if req.Name != "" || search.Name == "" {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = search.Query
+		}
+		if len(name) > 100 {
+			return fmt.Errorf("Name must be less than 100 characters")
+		}
+		search.Name = name
+	}
+
+	if req.Frequency != "" {
+		if !models.IsValidSavedSearchFrequency(req.Frequency) {
+			return fmt.Errorf("Frequency must be one of hourly, daily, weekly")
+		}
+		search.Frequency = req.Frequency
+	}
+
+	if req.AlertsEnabled != nil {
+		search.AlertsEnabled = *req.AlertsEnabled
+	}
+
+	if req.Filters != nil {
+		values := url.Values{}
+		for _, key := range savedSearchFilterKeys {
+			if value := strings.TrimSpace(req.Filters[key]); value != "" {
+				values.Set(key, value)
+			}
+		}
+		if _, err := services.ParseRoomSearchFilters(values); err != nil {
+			return err
+		}
+		search.Filters = values.Encode()
+	}
+
+	return nil
+}
+
+func savedSearchResponse(search models.SavedSearch) gin.H {
+	filters := map[string]string{}
+	if values, err := url.ParseQuery(search.Filters); err == nil {
+		for key := range values {
+			filters[key] = values.Get(key)
+		}
+	}
+
+	return gin.H{
+		"id":             search.ID,
+		"name":           search.Name,
+		"query":          search.Query,
+		"filters":        filters,
+		"frequency":      search.Frequency,
+		"alerts_enabled": search.AlertsEnabled,
+		"last_run_at":    search.LastRunAt,
+		"next_run_at":    search.NextRunAt,
+		"created_at":     search.CreatedAt,
+	}
+}
@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"voxarena_server/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseRoomSearchFilters reads the room search filters from the query string.
+func parseRoomSearchFilters(c *gin.Context) (services.RoomSearchFilters, error) {
+	return services.ParseRoomSearchFilters(c.Request.URL.Query())
+}
@@ -7,14 +7,21 @@ import (
 	"time"
 	"voxarena_server/config"
 	"voxarena_server/models"
+	"voxarena_server/services"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type SearchResult struct {
-	Users []UserSearchResult `json:"users"`
-	Rooms []RoomSearchResult `json:"rooms"`
+	Users []UserSearchResult          `json:"users"`
+	Rooms []services.RoomSearchResult `json:"rooms"`
+	Posts []PostSearchResult          `json:"posts"`
+
+	PostsPage    int  `json:"posts_page"`
+	PostsHasMore bool `json:"posts_has_more"`
+
+	DidYouMean string `json:"did_you_mean,omitempty"`
 }
 
 type UserSearchResult struct {
@@ -28,26 +35,32 @@ type UserSearchResult struct {
 	TotalAudios    int    `json:"total_audios"`
 	TotalListeners int    `json:"total_listeners"`
 	IsFollowing    bool   `json:"is_following"`
+
+	Score             float64 `json:"score"`
+	FullNameHighlight string  `json:"full_name_highlight"`
+	BioSnippet        string  `json:"bio_snippet"`
 }
 
-type RoomSearchResult struct {
-	ID                 uint   `json:"id"`
-	Title              string `json:"title"`
-	Description        string `json:"description"`
-	Topic              string `json:"topic"`
-	AudioURL           string `json:"audio_url"`
-	ThumbnailURL       string `json:"thumbnail_url"`
-	Duration           int    `json:"duration"`
-	HostID             uint   `json:"host_id"`
-	HostName           string `json:"host_name"`
-	HostAvatar         string `json:"host_avatar"`
-	HostFollowersCount int    `json:"host_followers_count"`
-	IsLive             bool   `json:"is_live"`
-	IsPrivate          bool   `json:"is_private"`
-	ListenerCount      int    `json:"listener_count"`
-	TotalListens       int    `json:"total_listens"`
-	LikesCount         int    `json:"likes_count"`
-	CreatedAt          string `json:"created_at"`
+// PostSearchResult is a community post, a community post comment or a room
+// comment. Type is "community_post", "community_comment" or "room_comment",
+// and CommunityPostID/RoomID point at the parent.
+type PostSearchResult struct {
+	Type            string  `json:"type"`
+	ID              uint    `json:"id"`
+	CommunityPostID *uint   `json:"community_post_id,omitempty"`
+	RoomID          *uint   `json:"room_id,omitempty"`
+	Content         string  `json:"content"`
+	ContentSnippet  string  `json:"content_snippet"`
+	AudioURL        string  `json:"audio_url,omitempty"`
+	LikesCount      int     `json:"likes_count"`
+	CommentsCount   int     `json:"comments_count"`
+	UserID          uint    `json:"user_id"`
+	Username        string  `json:"username"`
+	FullName        string  `json:"full_name"`
+	ProfilePic      string  `json:"profile_pic"`
+	IsVerified      bool    `json:"is_verified"`
+	CreatedAt       string  `json:"created_at"`
+	Score           float64 `json:"score"`
 }
 
 func GlobalSearch(c *gin.Context) {
@@ -79,6 +92,15 @@ func GlobalSearch(c *gin.Context) {
 	}
 
 	searchType := c.Query("type")
+
+	filters, err := parseRoomSearchFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filters",
+			"message": err.Error(),
+		})
+		return
+	}
 	limit := 20
 	if limitParam := c.Query("limit"); limitParam != "" {
 		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 100 {
@@ -100,9 +122,16 @@ func GlobalSearch(c *gin.Context) {
 		}
 	}
 
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
 	result := SearchResult{
-		Users: []UserSearchResult{},
-		Rooms: []RoomSearchResult{},
+		Users:     []UserSearchResult{},
+		Rooms:     []services.RoomSearchResult{},
+		Posts:     []PostSearchResult{},
+		PostsPage: page,
 	}
 
 	if searchType == "" || searchType == "all" || searchType == "users" {
@@ -115,7 +144,7 @@ func GlobalSearch(c *gin.Context) {
 	}
 
 	if searchType == "" || searchType == "all" || searchType == "rooms" {
-		rooms, err := searchRooms(db, query, currentUserID, limit)
+		rooms, err := services.SearchRooms(db, query, currentUserID, filters, limit)
 		if err != nil {
 			println("Error searching rooms:", err.Error())
 		} else {
@@ -123,9 +152,35 @@ func GlobalSearch(c *gin.Context) {
 		}
 	}
 
+	if searchType == "" || searchType == "all" || searchType == "posts" || searchType == "comments" {
+		includePosts := searchType != "comments"
+		posts, hasMore, err := searchPosts(db, query, currentUserID, includePosts, limit, (page-1)*limit)
+		if err != nil {
+			println("Error searching posts:", err.Error())
+		} else {
+			result.Posts = posts
+			result.PostsHasMore = hasMore
+		}
+	}
+
+	resultCount := len(result.Users) + len(result.Rooms) + len(result.Posts)
+	if page == 1 {
+		logSearchQuery(db, query, searcherKey(c), resultCount)
+	}
+	if resultCount == 0 {
+		result.DidYouMean = suggestSpelling(db, query)
+	}
+
 	c.JSON(http.StatusOK, result)
 }
 
+type userSearchRow struct {
+	models.User
+	Score             float64
+	FullNameHighlight string
+	BioSnippet        string
+}
+
 func searchUsers(
 	db *gorm.DB,
 	query string,
@@ -133,31 +188,50 @@ func searchUsers(
 	limit int,
 ) ([]UserSearchResult, error) {
 
-	var users []models.User
-	searchPattern := "%" + strings.ToLower(query) + "%"
-
-	q := db.
-		Where(
-			"LOWER(username) LIKE ? OR LOWER(full_name) LIKE ?",
-			searchPattern,
-			searchPattern,
-		).
-		Where("is_active = ?", true)
-
-	if currentUserID > 0 {
-		q = q.Where(`
-			id NOT IN (
-				SELECT user_id
-				FROM hidden_users
-				WHERE hidden_user_id = ?
-			)
-		`, currentUserID)
-	}
-
-	err := q.
-		Order("followers_count DESC, created_at DESC").
-		Limit(limit).
-		Find(&users).Error
+	// Text relevance dominates; followers only break ties between similar matches.
+	sql := `
+		WITH params AS (
+			SELECT to_tsquery('simple', @tsquery) AS tsq
+		),
+		matches AS (
+			SELECT users.*,
+				ts_rank_cd(users.search_vector, params.tsq, 32) AS text_rank,
+				GREATEST(similarity(users.username, @query), similarity(users.full_name, @query)) AS name_similarity
+			FROM users, params
+			WHERE users.deleted_at IS NULL
+				AND users.is_active = true
+				AND (
+					users.search_vector @@ params.tsq
+					OR users.username % @query
+					OR users.full_name % @query
+				)
+				AND (@viewer = 0 OR users.id NOT IN (
+					SELECT user_id FROM hidden_users WHERE hidden_user_id = @viewer
+				))
+		),
+		ranked AS (
+			SELECT matches.*,
+				0.8 * (text_rank + name_similarity) + 0.2 * LN(1 + followers_count) / 10 AS score
+			FROM matches
+			ORDER BY score DESC, followers_count DESC, created_at DESC
+			LIMIT @limit
+		)
+		SELECT ranked.*,
+			ts_headline('simple', ` + services.HighlightSource("ranked.full_name") + `, params.tsq, @headline) AS full_name_highlight,
+			ts_headline('simple', ` + services.HighlightSource("ranked.bio") + `, params.tsq, @snippet) AS bio_snippet
+		FROM ranked, params
+		ORDER BY ranked.score DESC, ranked.followers_count DESC, ranked.created_at DESC
+	`
+
+	var users []userSearchRow
+	err := db.Raw(sql, map[string]interface{}{
+		"tsquery":  services.BuildPrefixTSQuery(query),
+		"query":    query,
+		"viewer":   currentUserID,
+		"limit":    limit,
+		"headline": services.SearchHeadlineOptions,
+		"snippet":  services.SearchSnippetOptions,
+	}).Scan(&users).Error
 
 	if err != nil {
 		return nil, err
@@ -169,12 +243,12 @@ func searchUsers(
 
 		var totalAudios int64
 		db.Model(&models.Room{}).
-			Where("host_id = ? AND is_private = ?", user.ID, false).
+			Where("host_id = ? AND is_private = ? AND status = ?", user.ID, false, models.RoomStatusPublished).
 			Count(&totalAudios)
 
 		var totalListeners int64
 		db.Model(&models.Room{}).
-			Where("host_id = ? AND is_private = ?", user.ID, false).
+			Where("host_id = ? AND is_private = ? AND status = ?", user.ID, false, models.RoomStatusPublished).
 			Select("COALESCE(SUM(total_listens), 0)").
 			Scan(&totalListeners)
 
@@ -192,85 +266,159 @@ func searchUsers(
 		}
 
 		results = append(results, UserSearchResult{
-			ID:             user.ID,
-			Username:       user.Username,
-			FullName:       user.FullName,
-			ProfilePic:     user.ProfilePic,
-			Bio:            user.Bio,
-			IsVerified:     user.IsVerified,
-			FollowersCount: user.FollowersCount,
-			TotalAudios:    int(totalAudios),
-			TotalListeners: int(totalListeners),
-			IsFollowing:    isFollowing,
+			ID:                user.ID,
+			Username:          user.Username,
+			FullName:          user.FullName,
+			ProfilePic:        user.ProfilePic,
+			Bio:               user.Bio,
+			IsVerified:        user.IsVerified,
+			FollowersCount:    user.FollowersCount,
+			TotalAudios:       int(totalAudios),
+			TotalListeners:    int(totalListeners),
+			IsFollowing:       isFollowing,
+			Score:             user.Score,
+			FullNameHighlight: user.FullNameHighlight,
+			BioSnippet:        user.BioSnippet,
 		})
 	}
 
 	return results, nil
 }
 
-func searchRooms(
+type postSearchRow struct {
+	Type            string
+	ID              uint
+	CommunityPostID *uint
+	RoomID          *uint
+	Content         string
+	ContentSnippet  string
+	AudioURL        string
+	LikesCount      int
+	CommentsCount   int
+	UserID          uint
+	Username        string
+	FullName        string
+	ProfilePic      string
+	IsVerified      bool
+	CreatedAt       time.Time
+	Score           float64
+}
+
+// searchPosts ranks community posts, community post comments and room comments
+// together. Content by authors who hid the viewer is skipped, as are comments
+// whose parent post or room is deleted, private, hidden or owned by such an
+// author. When includePosts is false only comments are searched.
+func searchPosts(
 	db *gorm.DB,
 	query string,
 	currentUserID uint,
+	includePosts bool,
 	limit int,
-) ([]RoomSearchResult, error) {
-
-	var rooms []models.Room
-	searchPattern := "%" + strings.ToLower(query) + "%"
-
-	q := db.
-		Preload("Host").
-		Where("is_private = ?", false).
-		Where("is_hidden = ?", false).
-		Where(
-			"LOWER(title) LIKE ? OR LOWER(description) LIKE ? OR LOWER(topic) LIKE ?",
-			searchPattern,
-			searchPattern,
-			searchPattern,
+	offset int,
+) ([]PostSearchResult, bool, error) {
+
+	sql := `
+		WITH params AS (
+			SELECT to_tsquery('english', @tsquery) AS tsq
+		),
+		hidden_authors AS (
+			SELECT user_id FROM hidden_users WHERE hidden_user_id = @viewer
+		),
+		matches AS (
+			SELECT 'community_post' AS type, p.id, p.id AS community_post_id, NULL::bigint AS room_id,
+				p.content, p.audio_url, p.likes_count, p.comments_count, p.user_id, p.created_at,
+				ts_rank_cd(p.search_vector, params.tsq, 32) AS text_rank
+			FROM community_posts p, params
+			WHERE @include_posts
+				AND p.deleted_at IS NULL
+				AND p.search_vector @@ params.tsq
+				AND p.user_id NOT IN (SELECT user_id FROM hidden_authors)
+
+			UNION ALL
+
+			SELECT 'community_comment', c.id, c.community_post_id, NULL::bigint,
+				c.content, '', c.likes_count, 0, c.user_id, c.created_at,
+				ts_rank_cd(c.search_vector, params.tsq, 32)
+			FROM community_post_comments c
+			INNER JOIN community_posts p ON p.id = c.community_post_id AND p.deleted_at IS NULL, params
+			WHERE c.deleted_at IS NULL
+				AND c.search_vector @@ params.tsq
+				AND c.user_id NOT IN (SELECT user_id FROM hidden_authors)
+				AND p.user_id NOT IN (SELECT user_id FROM hidden_authors)
+
+			UNION ALL
+
+			SELECT 'room_comment', c.id, NULL::bigint, c.room_id,
+				c.content, '', c.likes_count, 0, c.user_id, c.created_at,
+				ts_rank_cd(c.search_vector, params.tsq, 32)
+			FROM comments c
+			INNER JOIN rooms r ON r.id = c.room_id AND r.deleted_at IS NULL, params
+			WHERE c.deleted_at IS NULL
+				AND r.is_private = false
+				AND r.is_hidden = false
+				AND r.status = 'published'
+				AND c.search_vector @@ params.tsq
+				AND c.user_id NOT IN (SELECT user_id FROM hidden_authors)
+				AND r.host_id NOT IN (SELECT user_id FROM hidden_authors)
+		),
+		ranked AS (
+			SELECT matches.*,
+				0.8 * text_rank + 0.2 * LN(1 + likes_count + comments_count) / 10 AS score
+			FROM matches
+			INNER JOIN users u ON u.id = matches.user_id AND u.deleted_at IS NULL AND u.is_active = true
+			ORDER BY score DESC, matches.created_at DESC, matches.id DESC
+			LIMIT @limit OFFSET @offset
 		)
+		SELECT ranked.type, ranked.id, ranked.community_post_id, ranked.room_id,
+			ranked.content, ranked.audio_url, ranked.likes_count, ranked.comments_count,
+			ranked.user_id, ranked.created_at, ranked.score,
+			u.username, u.full_name, u.profile_pic, u.is_verified,
+			ts_headline('english', ` + services.HighlightSource("ranked.content") + `, params.tsq, @snippet) AS content_snippet
+		FROM ranked
+		INNER JOIN users u ON u.id = ranked.user_id, params
+		ORDER BY ranked.score DESC, ranked.created_at DESC, ranked.id DESC
+	`
+
+	var rows []postSearchRow
+	err := db.Raw(sql, map[string]interface{}{
+		"tsquery":       services.BuildPrefixTSQuery(query),
+		"viewer":        currentUserID,
+		"include_posts": includePosts,
+		"limit":         limit + 1,
+		"offset":        offset,
+		"snippet":       services.SearchSnippetOptions,
+	}).Scan(&rows).Error
 
-	if currentUserID > 0 {
-		q = q.Where(`
-			host_id NOT IN (
-				SELECT user_id
-				FROM hidden_users
-				WHERE hidden_user_id = ?
-			)
-		`, currentUserID)
+	if err != nil {
+		return nil, false, err
 	}
 
-	err := q.
-		Order("total_listens DESC, created_at DESC").
-		Limit(limit).
-		Find(&rooms).Error
-
-	if err != nil {
-		return nil, err
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
 	}
 
-	results := make([]RoomSearchResult, 0, len(rooms))
-
-	for _, room := range rooms {
-		results = append(results, RoomSearchResult{
-			ID:                 room.ID,
-			Title:              room.Title,
-			Description:        room.Description,
-			Topic:              room.Topic,
-			AudioURL:           room.AudioURL,
-			ThumbnailURL:       room.ThumbnailURL,
-			Duration:           room.Duration,
-			HostID:             room.HostID,
-			HostName:           room.Host.FullName,
-			HostAvatar:         room.Host.ProfilePic,
-			HostFollowersCount: room.Host.FollowersCount,
-			IsLive:             room.IsLive,
-			IsPrivate:          room.IsPrivate,
-			ListenerCount:      room.ListenerCount,
-			TotalListens:       room.TotalListens,
-			LikesCount:         room.LikesCount,
-			CreatedAt:          room.CreatedAt.Format(time.RFC3339),
+	results := make([]PostSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, PostSearchResult{
+			Type:            row.Type,
+			ID:              row.ID,
+			CommunityPostID: row.CommunityPostID,
+			RoomID:          row.RoomID,
+			Content:         row.Content,
+			ContentSnippet:  row.ContentSnippet,
+			AudioURL:        row.AudioURL,
+			LikesCount:      row.LikesCount,
+			CommentsCount:   row.CommentsCount,
+			UserID:          row.UserID,
+			Username:        row.Username,
+			FullName:        row.FullName,
+			ProfilePic:      row.ProfilePic,
+			IsVerified:      row.IsVerified,
+			CreatedAt:       row.CreatedAt.Format(time.RFC3339),
+			Score:           row.Score,
 		})
 	}
 
-	return results, nil
+	return results, hasMore, nil
 }
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// suggestionTimeout is the latency budget for type-ahead lookups; whatever has
+// not been found by then is dropped rather than delaying the response.
+const suggestionTimeout = 300 * time.Millisecond
+
+// trendingSearchHalfLife controls how fast a search stops counting towards
+// trending, and trendingSearchMinCount keeps rare (and possibly personal)
+// queries out of the public list until that many different people ran them
+// (counted per day, since searcher hashes rotate daily).
+const (
+	trendingSearchHalfLife = 24 * time.Hour
+	trendingSearchWindow   = 7 * 24 * time.Hour
+	trendingSearchMinCount = 3
+)
+
+type UserSuggestion struct {
+	ID         uint   `json:"id"`
+	Username   string `json:"username"`
+	FullName   string `json:"full_name"`
+	ProfilePic string `json:"profile_pic"`
+	IsVerified bool   `json:"is_verified"`
+}
+
+type RoomSuggestion struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Topic string `json:"topic"`
+}
+
+type TrendingSearch struct {
+	Query    string  `json:"query"`
+	Searches int     `json:"searches"`
+	Score    float64 `json:"score"`
+}
+
+func GetSearchSuggestions(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		return
+	}
+	if len(query) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query must be less than 100 characters"})
+		return
+	}
+
+	limit := 5
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 10 {
+		limit = l
+	}
+
+	currentUserID := c.GetUint("user_id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), suggestionTimeout)
+	defer cancel()
+	db := config.DB.WithContext(ctx)
+
+	prefix := escapeLikePattern(strings.ToLower(query)) + "%"
+
+	users := []UserSuggestion{}
+	userQuery := db.Model(&models.User{}).
+		Select("id, username, full_name, profile_pic, is_verified").
+		Where("LOWER(username) LIKE ?", prefix).
+		Where("is_active = ?", true)
+	if currentUserID > 0 {
+		userQuery = userQuery.Where("id NOT IN (SELECT user_id FROM hidden_users WHERE hidden_user_id = ?)", currentUserID)
+	}
+	if err := userQuery.Order("followers_count DESC").Limit(limit).Scan(&users).Error; err != nil {
+		log.Printf("Error suggesting users: %v", err)
+	}
+
+	rooms := []RoomSuggestion{}
+	roomQuery := db.Model(&models.Room{}).
+		Select("id, title, topic").
+		Where("LOWER(title) LIKE ?", prefix).
+		Where("is_private = ? AND is_hidden = ? AND status = ?", false, false, models.RoomStatusPublished)
+	if currentUserID > 0 {
+		roomQuery = roomQuery.Where("host_id NOT IN (SELECT user_id FROM hidden_users WHERE hidden_user_id = ?)", currentUserID)
+	}
+	if err := roomQuery.Order("total_listens DESC").Limit(limit).Scan(&rooms).Error; err != nil {
+		log.Printf("Error suggesting rooms: %v", err)
+	}
+
+	topics := []string{}
+	if err := db.Model(&models.Room{}).
+		Select("topic").
+		Where("LOWER(topic) LIKE ?", prefix).
+		Where("is_private = ? AND is_hidden = ? AND status = ?", false, false, models.RoomStatusPublished).
+		Group("topic").
+		Order("COUNT(*) DESC").
+		Limit(limit).
+		Pluck("topic", &topics).Error; err != nil {
+		log.Printf("Error suggesting topics: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":  query,
+		"users":  users,
+		"rooms":  rooms,
+		"topics": topics,
+	})
+}
+
+func GetTrendingSearches(c *gin.Context) {
+	db := config.DB
+
+	limit := 10
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+
+	trending := []TrendingSearch{}
+	err := db.Raw(`
+		SELECT query,
+			COUNT(*) AS searches,
+			SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - created_at)) / @half_life)) AS score
+		FROM search_query_logs
+		WHERE created_at > @since AND result_count > 0
+		GROUP BY query
+		HAVING COUNT(DISTINCT NULLIF(searcher_hash, '')) >= @min_count
+		ORDER BY score DESC
+		LIMIT @limit
+	`, map[string]interface{}{
+		"half_life": trendingSearchHalfLife.Seconds(),
+		"since":     time.Now().Add(-trendingSearchWindow),
+		"min_count": trendingSearchMinCount,
+		"limit":     limit,
+	}).Scan(&trending).Error
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trending searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"trending": trending,
+	})
+}
+
+// searcherKey identifies the signed-in user, or the client IP for anonymous
+// searches. Only its salted hash is stored.
+func searcherKey(c *gin.Context) string {
+	if userID := c.GetUint("user_id"); userID != 0 {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// logSearchQuery stores the normalized query and how many results it found.
+// It runs in the background so logging never slows down a search.
+func logSearchQuery(db *gorm.DB, query, searcher string, resultCount int) {
+	normalized := models.NormalizeSearchQuery(query)
+	if normalized == "" {
+		return
+	}
+
+	go func() {
+		hash, err := models.HashSearcher(db, searcher)
+		if err != nil {
+			log.Printf("Error hashing searcher: %v", err)
+		}
+		entry := models.SearchQueryLog{Query: normalized, ResultCount: resultCount, SearcherHash: hash}
+		if err := db.Create(&entry).Error; err != nil {
+			log.Printf("Error logging search query: %v", err)
+		}
+	}()
+}
+
+// suggestSpelling returns the closest username, room title or popular past
+// query to query, or "" if nothing is similar enough.
+func suggestSpelling(db *gorm.DB, query string) string {
+	normalized := models.NormalizeSearchQuery(query)
+
+	var suggestion string
+	err := db.Raw(`
+		SELECT term FROM (
+			SELECT username AS term, similarity(username, @query) AS sim
+			FROM users
+			WHERE deleted_at IS NULL AND is_active = true AND username % @query
+
+			UNION ALL
+
+			SELECT title, similarity(title, @query)
+			FROM rooms
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false AND status = 'published' AND title % @query
+
+			UNION ALL
+
+			SELECT query, similarity(query, @query)
+			FROM search_query_logs
+			WHERE result_count > 0 AND query % @query
+			GROUP BY query
+			HAVING COUNT(DISTINCT NULLIF(searcher_hash, '')) >= @min_count
+		) candidates
+		WHERE LOWER(term) <> @query
+		ORDER BY sim DESC
+		LIMIT 1
+	`, map[string]interface{}{
+		"query":     normalized,
+		"min_count": trendingSearchMinCount,
+	}).Scan(&suggestion).Error
+
+	if err != nil {
+		log.Printf("Error building spelling suggestion: %v", err)
+		return ""
+	}
+	return suggestion
+}
+
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
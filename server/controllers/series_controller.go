@@ -0,0 +1,585 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"voxarena_server/config"
+	"voxarena_server/models"
+	"voxarena_server/services"
+	"voxarena_server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxSeriesTitleLength = 150
+
+var errEpisodeSlotTaken = errors.New("episode slot taken")
+
+type SeriesEpisodeRequest struct {
+	SeasonNumber  *int `json:"season_number" binding:"omitempty,min=1"`
+	EpisodeNumber *int `json:"episode_number" binding:"omitempty,min=1"`
+}
+
+func findSeries(c *gin.Context) (*models.Series, bool) {
+	seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return nil, false
+	}
+
+	var series models.Series
+	if err := models.WithEpisodesCount(config.DB.Model(&models.Series{})).
+		Preload("Host").
+		First(&series, seriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return nil, false
+	}
+	return &series, true
+}
+
+func findOwnedSeries(c *gin.Context, userID uint) (*models.Series, bool) {
+	series, ok := findSeries(c)
+	if !ok {
+		return nil, false
+	}
+	if series.HostID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found or unauthorized"})
+		return nil, false
+	}
+	return series, true
+}
+
+// parseEpisodeNumber reads an optional positive season or episode number
+// from a form value.
+func parseEpisodeNumber(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return nil, fmt.Errorf("must be a positive number")
+	}
+	return &number, nil
+}
+
+// resolveEpisodeSlot fills in a missing season (1) or episode number (next
+// free in the season) and rejects slots another room already holds. It locks
+// the series row, so tx must be a transaction.
+func resolveEpisodeSlot(tx *gorm.DB, seriesID, roomID uint, season, episode *int) (int, int, error) {
+	var locked models.Series
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, seriesID).Error; err != nil {
+		return 0, 0, err
+	}
+
+	seasonNumber := 1
+	if season != nil {
+		seasonNumber = *season
+	}
+
+	if episode == nil {
+		next, err := models.NextEpisodeNumber(tx, seriesID, seasonNumber)
+		return seasonNumber, next, err
+	}
+
+	taken, err := models.IsEpisodeSlotTaken(tx, seriesID, seasonNumber, *episode, roomID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if taken {
+		return 0, 0, errEpisodeSlotTaken
+	}
+	return seasonNumber, *episode, nil
+}
+
+// applySeriesForm reads title, description, category and artwork from a
+// multipart form. Fields that are absent are left unchanged.
+func applySeriesForm(c *gin.Context, series *models.Series, userID uint) bool {
+	if title, ok := c.GetPostForm("title"); ok {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+			return false
+		}
+		if len([]rune(title)) > maxSeriesTitleLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Title must be at most %d characters", maxSeriesTitleLength)})
+			return false
+		}
+		series.Title = title
+	}
+
+	if description, ok := c.GetPostForm("description"); ok {
+		series.Description = strings.TrimSpace(description)
+	}
+
+	if category, ok := c.GetPostForm("category"); ok {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			series.Category = ""
+			series.TopicID = nil
+		} else {
+			topic, err := models.FindActiveTopic(config.DB, category)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+				}
+				return false
+			}
+			series.Category = topic.Name
+			series.TopicID = &topic.ID
+		}
+	}
+
+	if c.PostForm("remove_artwork") == "true" {
+		series.ArtworkURL = ""
+	}
+
+	artworkFile, artworkHeader, err := c.Request.FormFile("artwork")
+	if err == nil {
+		defer artworkFile.Close()
+
+		if artworkHeader.Size > 5*1024*1024 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Artwork must be at most 5MB"})
+			return false
+		}
+
+		artworkData, err := io.ReadAll(artworkFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read artwork"})
+			return false
+		}
+
+		artworkURL, err := utils.UploadThumbnailToCloudinary(artworkData, fmt.Sprint(userID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload artwork"})
+			return false
+		}
+		series.ArtworkURL = artworkURL
+	}
+
+	return true
+}
+
+func CreateSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if _, ok := c.GetPostForm("title"); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+		return
+	}
+
+	series := models.Series{HostID: userID}
+	if !applySeriesForm(c, &series, userID) {
+		return
+	}
+
+	if err := config.DB.Create(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series"})
+		return
+	}
+
+	config.DB.Preload("Host").First(&series, series.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"series":  series,
+	})
+}
+
+func UpdateSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findOwnedSeries(c, userID)
+	if !ok {
+		return
+	}
+
+	if !applySeriesForm(c, series, userID) {
+		return
+	}
+
+	if err := config.DB.Model(series).
+		Select("title", "description", "category", "topic_id", "artwork_url").
+		Updates(series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"series":  series,
+	})
+}
+
+// DeleteSeries removes the series; its rooms stay as standalone rooms.
+func DeleteSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findOwnedSeries(c, userID)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Room{}).Where("series_id = ?", series.ID).
+			Updates(map[string]interface{}{"series_id": nil, "season_number": nil, "episode_number": nil}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("series_id = ?", series.ID).Delete(&models.SeriesSubscription{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(series).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Series deleted",
+	})
+}
+
+// GetSeries returns a series with its episodes in season and episode order.
+func GetSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findSeries(c)
+	if !ok {
+		return
+	}
+
+	hiddenByUserIDs, err := GetUsersWhoHidMe(config.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden users"})
+		return
+	}
+	for _, id := range hiddenByUserIDs {
+		if id == series.HostID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+	}
+
+	query := config.DB.Model(&models.Room{}).
+		Where("series_id = ?", series.ID)
+	if series.HostID != userID {
+		query = query.Where("is_private = ? AND is_hidden = ? AND status = ?", false, false, models.RoomStatusPublished)
+	}
+
+	episodes := make([]models.Room, 0)
+	if err := query.
+		Preload("Host").
+		Order("season_number ASC, episode_number ASC, id ASC").
+		Find(&episodes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch episodes"})
+		return
+	}
+
+	var subscribed int64
+	config.DB.Model(&models.SeriesSubscription{}).
+		Where("series_id = ? AND user_id = ?", series.ID, userID).
+		Count(&subscribed)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"series":        series,
+		"episodes":      episodes,
+		"is_owner":      series.HostID == userID,
+		"is_subscribed": subscribed > 0,
+	})
+}
+
+func GetUserSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	targetUserID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	pagination, err := parsePagination(c, createdAtKeys("series")...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var hidden int64
+	config.DB.Model(&models.HiddenUser{}).
+		Where("user_id = ? AND hidden_user_id = ?", targetUserID, userID).
+		Count(&hidden)
+
+	series := make([]models.Series, 0)
+	if hidden == 0 {
+		query := models.WithEpisodesCount(config.DB.Model(&models.Series{})).
+			Where("series.host_id = ?", targetUserID)
+		if err := pagination.Apply(query.Preload("Host")).Find(&series).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+			return
+		}
+	}
+
+	series, nextCursor := finishPage(pagination, series, func(s models.Series) []interface{} {
+		return []interface{}{s.CreatedAt, s.ID}
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"series":      series,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
+
+// SetSeriesEpisode adds one of the host's rooms to the series or changes its
+// season and episode number. Subscribers are notified when a published room
+// joins the series; drafts notify them once they are published.
+func SetSeriesEpisode(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findOwnedSeries(c, userID)
+	if !ok {
+		return
+	}
+
+	var req SeriesEpisodeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var room models.Room
+	if err := config.DB.Where("id = ? AND host_id = ?", c.Param("roomId"), userID).First(&room).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	isNewEpisode := room.SeriesID == nil || *room.SeriesID != series.ID
+	if !isNewEpisode && req.SeasonNumber == nil && req.EpisodeNumber == nil {
+		c.JSON(http.StatusOK, gin.H{"success": true, "episode": room})
+		return
+	}
+	if !isNewEpisode && req.SeasonNumber == nil {
+		req.SeasonNumber = room.SeasonNumber
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		season, episode, err := resolveEpisodeSlot(tx, series.ID, room.ID, req.SeasonNumber, req.EpisodeNumber)
+		if err != nil {
+			return err
+		}
+
+		room.SeriesID = &series.ID
+		room.SeasonNumber = &season
+		room.EpisodeNumber = &episode
+		return tx.Model(&room).
+			Select("series_id", "season_number", "episode_number").
+			Updates(&room).Error
+	})
+	if errors.Is(err, errEpisodeSlotTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another room already has this season and episode number"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update episode"})
+		return
+	}
+
+	if isNewEpisode && room.Status == models.RoomStatusPublished {
+		notificationService := services.NewNotificationService(config.DB)
+		go func() {
+			if err := notificationService.NotifyNewEpisode(&room); err != nil {
+				log.Printf("⚠️ Failed to send episode notifications: %v", err)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"episode": room,
+	})
+}
+
+func RemoveSeriesEpisode(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findOwnedSeries(c, userID)
+	if !ok {
+		return
+	}
+
+	result := config.DB.Model(&models.Room{}).
+		Where("id = ? AND series_id = ?", c.Param("roomId"), series.ID).
+		Updates(map[string]interface{}{"series_id": nil, "season_number": nil, "episode_number": nil})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove episode"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Episode not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Episode removed from series",
+	})
+}
+
+func SubscribeSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	series, ok := findSeries(c)
+	if !ok {
+		return
+	}
+
+	if series.HostID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't subscribe to your own series"})
+		return
+	}
+
+	var hidden int64
+	config.DB.Model(&models.HiddenUser{}).
+		Where("user_id = ? AND hidden_user_id = ?", series.HostID, userID).
+		Count(&hidden)
+	if hidden > 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		subscription := models.SeriesSubscription{SeriesID: series.ID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&subscription)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Series{}).Where("id = ?", series.ID).
+			Update("subscribers_count", gorm.Expr("subscribers_count + ?", 1)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to subscribe"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"is_subscribed": true,
+	})
+}
+
+func UnsubscribeSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	seriesID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("series_id = ? AND user_id = ?", seriesID, userID).Delete(&models.SeriesSubscription{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Series{}).Where("id = ? AND subscribers_count > 0", seriesID).
+			Update("subscribers_count", gorm.Expr("subscribers_count - ?", 1)).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unsubscribe"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"is_subscribed": false,
+	})
+}
+
+func GetSubscribedSeries(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	pagination, err := parsePagination(c,
+		SortKey{Column: "series_subscriptions.created_at", Desc: true},
+		SortKey{Column: "series_subscriptions.id", Desc: true},
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hiddenByUserIDs, err := GetUsersWhoHidMe(config.DB, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hidden users"})
+		return
+	}
+
+	query := config.DB.Model(&models.SeriesSubscription{}).
+		Select("series_subscriptions.*").
+		Joins("JOIN series ON series.id = series_subscriptions.series_id AND series.deleted_at IS NULL").
+		Where("series_subscriptions.user_id = ?", userID)
+	if len(hiddenByUserIDs) > 0 {
+		query = query.Where("series.host_id NOT IN ?", hiddenByUserIDs)
+	}
+
+	var subscriptions []models.SeriesSubscription
+	if err := pagination.Apply(query).Find(&subscriptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subscriptions"})
+		return
+	}
+
+	subscriptions, nextCursor := finishPage(pagination, subscriptions, func(s models.SeriesSubscription) []interface{} {
+		return []interface{}{s.CreatedAt, s.ID}
+	})
+
+	series := make([]models.Series, 0, len(subscriptions))
+	if len(subscriptions) > 0 {
+		ids := make([]uint, 0, len(subscriptions))
+		for _, s := range subscriptions {
+			ids = append(ids, s.SeriesID)
+		}
+
+		var found []models.Series
+		if err := models.WithEpisodesCount(config.DB.Model(&models.Series{})).
+			Preload("Host").
+			Where("series.id IN ?", ids).
+			Find(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+			return
+		}
+		byID := make(map[uint]models.Series, len(found))
+		for _, s := range found {
+			byID[s.ID] = s
+		}
+		for _, id := range ids {
+			if s, ok := byID[id]; ok {
+				series = append(series, s)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"series":      series,
+		"limit":       pagination.Limit,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	})
+}
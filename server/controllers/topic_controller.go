@@ -0,0 +1,344 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetTopics lists the active topics in display order. "topics" keeps the
+// plain list of names (led by "All") that older clients render as chips.
+func GetTopics(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var topics []models.Topic
+	if err := db.Where("is_active = ?", true).
+		Order("position ASC, name ASC").
+		Find(&topics).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch topics"})
+		return
+	}
+
+	followed := map[uint]bool{}
+	if userID > 0 {
+		var followedIDs []uint
+		db.Model(&models.TopicFollow{}).Where("user_id = ?", userID).Pluck("topic_id", &followedIDs)
+		for _, id := range followedIDs {
+			followed[id] = true
+		}
+	}
+
+	names := make([]string, 0, len(topics)+1)
+	names = append(names, "All")
+	items := make([]gin.H, 0, len(topics))
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+		items = append(items, topicResponse(topic, followed[topic.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topics":  names,
+		"items":   items,
+	})
+}
+
+func GetFollowedTopics(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var topics []models.Topic
+	if err := config.DB.
+		Joins("JOIN topic_follows ON topic_follows.topic_id = topics.id").
+		Where("topic_follows.user_id = ? AND topics.is_active = ?", userID, true).
+		Order("topics.position ASC, topics.name ASC").
+		Find(&topics).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch followed topics"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(topics))
+	for _, topic := range topics {
+		items = append(items, topicResponse(topic, true))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topics":  items,
+	})
+}
+
+func FollowTopic(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var topic models.Topic
+	if err := db.Where("slug = ? AND is_active = ?", c.Param("slug"), true).First(&topic).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Topic not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	follow := models.TopicFollow{UserID: userID, TopicID: topic.ID}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow topic"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "Topic followed",
+		"topic":       topicResponse(topic, true),
+		"is_followed": true,
+	})
+}
+
+func UnfollowTopic(c *gin.Context) {
+	db := config.DB
+	userID := c.GetUint("user_id")
+
+	var topic models.Topic
+	if err := db.Where("slug = ?", c.Param("slug")).First(&topic).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Topic not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	if err := db.Where("user_id = ? AND topic_id = ?", userID, topic.ID).
+		Delete(&models.TopicFollow{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow topic"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "Topic unfollowed",
+		"is_followed": false,
+	})
+}
+
+type topicRequest struct {
+	Slug     *string `json:"slug"`
+	Name     *string `json:"name"`
+	Icon     *string `json:"icon"`
+	Position *int    `json:"position"`
+	IsActive *bool   `json:"is_active"`
+}
+
+// AdminGetTopics lists every topic, including inactive ones, with the number
+// of rooms filed under each.
+func AdminGetTopics(c *gin.Context) {
+	type topicWithCount struct {
+		models.Topic
+		RoomCount int64 `json:"room_count"`
+	}
+
+	var topics []topicWithCount
+	if err := config.DB.Model(&models.Topic{}).
+		Select("topics.*, (SELECT COUNT(*) FROM rooms WHERE rooms.topic_id = topics.id AND rooms.deleted_at IS NULL) AS room_count").
+		Order("position ASC, name ASC").
+		Scan(&topics).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch topics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topics":  topics,
+	})
+}
+
+func AdminCreateTopic(c *gin.Context) {
+	var req topicRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return
+	}
+
+	topic := models.Topic{IsActive: true}
+	if req.Slug == nil {
+		slug := *req.Name
+		req.Slug = &slug
+	}
+	if errMsg := applyTopicRequest(&topic, req); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&topic)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create topic"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A topic with this slug already exists"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"topic":   topic,
+	})
+}
+
+// AdminUpdateTopic edits a topic. Renames are copied onto the rooms filed
+// under it so Room.Topic keeps matching the taxonomy.
+func AdminUpdateTopic(c *gin.Context) {
+	db := config.DB
+
+	topicID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic ID"})
+		return
+	}
+
+	var topic models.Topic
+	if err := db.First(&topic, topicID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Topic not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	var req topicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if errMsg := applyTopicRequest(&topic, req); errMsg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+		return
+	}
+
+	var count int64
+	db.Model(&models.Topic{}).Where("slug = ? AND id <> ?", topic.Slug, topic.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A topic with this slug already exists"})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&topic).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Room{}).
+			Where("topic_id = ? AND topic <> ?", topic.ID, topic.Name).
+			Update("topic", topic.Name).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update topic"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topic":   topic,
+	})
+}
+
+// AdminDeleteTopic removes a topic that no rooms use. Topics with rooms must
+// be deactivated instead so existing rooms keep their classification.
+func AdminDeleteTopic(c *gin.Context) {
+	db := config.DB
+
+	topicID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic ID"})
+		return
+	}
+
+	var topic models.Topic
+	if err := db.First(&topic, topicID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Topic not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		return
+	}
+
+	var roomCount int64
+	db.Unscoped().Model(&models.Room{}).Where("topic_id = ?", topic.ID).Count(&roomCount)
+	if roomCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Topic has rooms; deactivate it instead",
+			"room_count": roomCount,
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("topic_id = ?", topic.ID).Delete(&models.TopicFollow{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&topic).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete topic"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Topic deleted",
+	})
+}
+
+func applyTopicRequest(topic *models.Topic, req topicRequest) string {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 64 {
+			return "Name must be between 1 and 64 characters"
+		}
+		if strings.EqualFold(name, "All") {
+			return "\"All\" is reserved"
+		}
+		topic.Name = name
+	}
+	if req.Slug != nil {
+		slug := models.Slugify(*req.Slug)
+		if slug == "" || len(slug) > 64 {
+			return "Slug must contain letters or digits and be at most 64 characters"
+		}
+		if slug == "all" {
+			return "\"all\" is reserved"
+		}
+		topic.Slug = slug
+	}
+	if req.Icon != nil {
+		topic.Icon = strings.TrimSpace(*req.Icon)
+	}
+	if req.Position != nil {
+		topic.Position = *req.Position
+	}
+	if req.IsActive != nil {
+		topic.IsActive = *req.IsActive
+	}
+	return ""
+}
+
+func topicResponse(topic models.Topic, isFollowed bool) gin.H {
+	return gin.H{
+		"id":          topic.ID,
+		"slug":        topic.Slug,
+		"name":        topic.Name,
+		"icon":        topic.Icon,
+		"position":    topic.Position,
+		"is_followed": isFollowed,
+	}
+}
@@ -3,15 +3,18 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
 	"voxarena_server/config"
 	"voxarena_server/models"
 	"voxarena_server/routes"
 	"voxarena_server/scheduler"
+	"voxarena_server/services"
 	"voxarena_server/utils"
 	"voxarena_server/websocket"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -41,6 +44,37 @@ func main() {
 		&models.UniqueRoomListen{},
 		&models.RoomReport{},
 		&models.Notification{},
+		&models.IdempotencyKey{},
+		&models.SearchQueryLog{},
+		&models.SearchQuerySalt{},
+		&models.SavedSearch{},
+		&models.RoomSimilarity{},
+		&models.FeedSeenItem{},
+		&models.Topic{},
+		&models.TopicFollow{},
+		&models.Hashtag{},
+		&models.RoomHashtag{},
+		&models.CommunityPostHashtag{},
+		&models.Mention{},
+		&models.GiftType{},
+		&models.Wallet{},
+		&models.LedgerTransaction{},
+		&models.LedgerEntry{},
+		&models.Gift{},
+		&models.RoomStat{},
+		&models.ChannelStat{},
+		&models.PlaybackSession{},
+		&models.RetentionCoverage{},
+		&models.RetentionHotspot{},
+		&models.PlaybackState{},
+		&models.Playlist{},
+		&models.PlaylistItem{},
+		&models.PlaylistCollaborator{},
+		&models.PlaylistFollow{},
+		&models.Series{},
+		&models.SeriesSubscription{},
+		&models.FeedImport{},
+		&models.FeedImportItem{},
 	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
@@ -54,6 +88,34 @@ func main() {
 		log.Println("✓ Comment system indexes created successfully")
 	}
 
+	if err := createDedupedUniqueIndex(`
+		DELETE FROM community_post_likes a USING community_post_likes b
+		WHERE a.community_post_id = b.community_post_id AND a.user_id = b.user_id
+			AND a.id > b.id AND a.deleted_at IS NULL AND b.deleted_at IS NULL
+	`, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_post_like
+		ON community_post_likes(community_post_id, user_id)
+		WHERE deleted_at IS NULL
+	`); err != nil {
+		log.Println("⚠️  Warning: Failed to create unique index on community_post_likes:", err)
+	} else {
+		log.Println("✓ Community post like indexes created successfully")
+	}
+
+	if err := createDedupedUniqueIndex(`
+		DELETE FROM community_comment_likes a USING community_comment_likes b
+		WHERE a.comment_id = b.comment_id AND a.user_id = b.user_id
+			AND a.id > b.id AND a.deleted_at IS NULL AND b.deleted_at IS NULL
+	`, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_comment_like
+		ON community_comment_likes(comment_id, user_id)
+		WHERE deleted_at IS NULL
+	`); err != nil {
+		log.Println("⚠️  Warning: Failed to create unique index on community_comment_likes:", err)
+	} else {
+		log.Println("✓ Community comment like indexes created successfully")
+	}
+
 	if err := config.DB.Exec(`
 	CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_room_listen ON unique_room_listens(room_id, user_id) WHERE deleted_at IS NULL
 	`).Error; err != nil {
@@ -62,11 +124,13 @@ func main() {
 		log.Println("✓ UniqueRoomListen indexes created successfully")
 	}
 
-	if err := config.DB.Exec(`
-    CREATE UNIQUE INDEX IF NOT EXISTS idx_room_report_unique
-    ON room_reports(room_id, reporter_id)
-    WHERE deleted_at IS NULL
-`).Error; err != nil {
+	if err := createDedupedUniqueIndex(`
+		DELETE FROM room_reports a USING room_reports b
+		WHERE a.room_id = b.room_id AND a.reporter_id = b.reporter_id AND a.id > b.id
+	`, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_room_report_unique
+		ON room_reports(room_id, reporter_id)
+	`); err != nil {
 		log.Println("⚠️  Warning: Failed to create unique index on room_reports:", err)
 	} else {
 		log.Println("✓ Room report unique index created successfully")
@@ -82,6 +146,50 @@ func main() {
 		log.Println("✓ Notification indexes created successfully")
 	}
 
+	if err := models.MigrateTopics(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to migrate topics:", err)
+	} else {
+		log.Println("✓ Topics migrated successfully")
+	}
+
+	if count, err := models.ClearLegacySearcherHashes(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to clear legacy searcher hashes:", err)
+	} else if count > 0 {
+		log.Printf("✓ Cleared legacy searcher hashes from %d search logs", count)
+	}
+
+	if count, err := models.BackfillRoomPublishedAt(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to backfill room publish times:", err)
+	} else if count > 0 {
+		log.Printf("✓ Backfilled publish times for %d rooms", count)
+	}
+
+	if count, err := models.BackfillHashtags(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to backfill hashtags:", err)
+	} else if count > 0 {
+		log.Printf("✓ Backfilled hashtags for %d rooms and posts", count)
+	}
+
+	if err := models.SeedGiftTypes(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to seed gift types:", err)
+	}
+
+	if err := models.SeedIssuanceWallet(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to seed issuance wallet:", err)
+	}
+
+	if err := services.InitPayments(); err != nil {
+		log.Println("⚠️  Warning: Coin top-ups disabled:", err)
+	} else {
+		log.Println("✓ Payment provider initialized")
+	}
+
+	if err := models.CreateSearchIndexes(config.DB); err != nil {
+		log.Println("⚠️  Warning: Failed to create search indexes:", err)
+	} else {
+		log.Println("✓ Search indexes created successfully")
+	}
+
 	if err := utils.InitCloudinary(); err != nil {
 		log.Println("⚠️  Warning: Cloudinary not initialized:", err)
 		log.Println("Profile picture uploads will use Google URLs as fallback")
@@ -93,8 +201,31 @@ func main() {
 log.Println("✓ WebSocket hub initialized")
 
 	router := gin.Default()
+
+	// Only proxies listed in TRUSTED_PROXIES may set X-Forwarded-For; with
+	// none configured, the client IP is the connecting address.
+	var trustedProxies []string
+	for _, proxy := range strings.Split(config.GetEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
 	routes.SetupRoutes(router)
 	scheduler.StartCleanupScheduler(config.DB)
+	scheduler.StartIdempotencyCleanupScheduler(config.DB)
+	scheduler.StartCounterReconcileScheduler(config.DB)
+	scheduler.StartSearchQueryCleanupScheduler(config.DB)
+	scheduler.StartSavedSearchAlertScheduler(config.DB)
+	scheduler.StartRecommendationScheduler(config.DB)
+	scheduler.StartTrendingScheduler(config.DB)
+	scheduler.StartAnalyticsScheduler(config.DB)
+	scheduler.StartFeedSeenCleanupScheduler(config.DB)
+	scheduler.StartFeedImportScheduler(config.DB)
+	scheduler.StartRoomPublishScheduler(config.DB)
 
 	// serverHost := config.GetEnv("SERVER_HOST", "0.0.0.0")
 	// serverPort := config.GetEnv("SERVER_PORT", "8090")
@@ -108,3 +239,15 @@ log.Println("✓ WebSocket hub initialized")
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// createDedupedUniqueIndex removes duplicate rows left from before a unique
+// index existed and then creates it. Counters inflated by the duplicates are
+// corrected by the counter reconciler.
+func createDedupedUniqueIndex(dedupe, create string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(dedupe).Error; err != nil {
+			return err
+		}
+		return tx.Exec(create).Error
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+// activeUserTTL bounds how long a ban can take to reach other server
+// instances; the instance handling the ban forgets the user immediately.
+const activeUserTTL = 30 * time.Second
+
+type activeUserEntry struct {
+	active    bool
+	checkedAt time.Time
+}
+
+var activeUsers = struct {
+	sync.Mutex
+	entries map[uint]activeUserEntry
+}{entries: map[uint]activeUserEntry{}}
+
+// isUserActive reports whether the account behind a token may still be used.
+// Deleted accounts count as inactive. Other database errors are let through
+// so a blip doesn't sign everyone out.
+func isUserActive(userID uint) bool {
+	now := time.Now()
+
+	activeUsers.Lock()
+	entry, ok := activeUsers.entries[userID]
+	activeUsers.Unlock()
+	if ok && now.Sub(entry.checkedAt) < activeUserTTL {
+		return entry.active
+	}
+
+	var user models.User
+	err := config.DB.Select("id", "is_active").First(&user, userID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to check account status of user %d: %v", userID, err)
+		return true
+	}
+	active := err == nil && user.IsActive
+
+	activeUsers.Lock()
+	if len(activeUsers.entries) > 50000 {
+		for id, cached := range activeUsers.entries {
+			if now.Sub(cached.checkedAt) >= activeUserTTL {
+				delete(activeUsers.entries, id)
+			}
+		}
+	}
+	activeUsers.entries[userID] = activeUserEntry{active: active, checkedAt: now}
+	activeUsers.Unlock()
+
+	return active
+}
+
+// ForgetUserStatus drops the cached account status after a ban or unban.
+func ForgetUserStatus(userID uint) {
+	activeUsers.Lock()
+	delete(activeUsers.entries, userID)
+	activeUsers.Unlock()
+}
@@ -44,6 +44,15 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if !isUserActive(claims.UserID) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Account is suspended",
+			})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
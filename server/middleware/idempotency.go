@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"time"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+const IdempotencyKeyRetention = 24 * time.Hour
+
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response when a mutating request is
+// retried with the same Idempotency-Key. It must run after AuthMiddleware since
+// keys are scoped per user.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader("Idempotency-Key")
+		userID := c.GetUint("user_id")
+		if key == "" || userID == 0 {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+
+		db := config.DB
+		record := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      method,
+			Path:        c.Request.URL.Path,
+			RequestHash: fingerprint,
+		}
+
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store idempotency key"})
+			c.Abort()
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			var existing models.IdempotencyKey
+			if err := db.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load idempotency key"})
+				c.Abort()
+				return
+			}
+
+			if existing.RequestHash != fingerprint {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "Idempotency-Key was already used with a different request",
+				})
+				c.Abort()
+				return
+			}
+
+			if existing.CompletedAt == nil {
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusConflict, gin.H{
+					"error": "A request with this Idempotency-Key is still being processed",
+				})
+				c.Abort()
+				return
+			}
+
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		defer func() {
+			// A crashed handler must not hold the key until it expires.
+			if r := recover(); r != nil {
+				db.Delete(&record)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			// Let the client retry failed or throttled requests with the same key.
+			db.Delete(&record)
+			return
+		}
+
+		now := time.Now()
+		db.Model(&record).Updates(map[string]interface{}{
+			"status_code":   status,
+			"content_type":  writer.Header().Get("Content-Type"),
+			"response_body": writer.body.Bytes(),
+			"completed_at":  &now,
+		})
+	}
+}
+
+// requestFingerprint identifies a request by method, path and content.
+// Multipart bodies are hashed field by field so a retry with a new boundary
+// still matches; uploads over the in-memory limit are parsed to temp files
+// and hashed as streams rather than read into memory.
+func requestFingerprint(c *gin.Context) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return "", err
+	}
+
+	fields := make([]string, 0, len(form.Value))
+	for name := range form.Value {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	for _, name := range fields {
+		for _, value := range form.Value[name] {
+			fmt.Fprintf(hash, "field %q %q\n", name, value)
+		}
+	}
+
+	files := make([]string, 0, len(form.File))
+	for name := range form.File {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	for _, name := range files {
+		for _, header := range form.File[name] {
+			file, err := header.Open()
+			if err != nil {
+				return "", err
+			}
+			content := sha256.New()
+			_, err = io.Copy(content, file)
+			file.Close()
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(hash, "file %q %q %x\n", name, header.Filename, content.Sum(nil))
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
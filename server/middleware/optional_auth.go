@@ -23,7 +23,7 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		}
 
 		claims, err := utils.ValidateJWT(tokenString)
-		if err != nil {
+		if err != nil || !isUserActive(claims.UserID) {
 			c.Next()
 			return
 		}
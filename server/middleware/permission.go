@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"voxarena_server/config"
+	"voxarena_server/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission must run after AuthMiddleware. The role is read from the
+// database rather than the JWT so that role changes apply immediately.
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		if userID == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		var user models.User
+		if err := config.DB.Select("id", "role", "is_active").First(&user, userID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if !user.IsActive || !models.RoleHasPermission(user.Role, permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":      "You do not have permission to perform this action",
+				"permission": permission,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPolicy allows Limit requests per Window, refilled continuously as a
+// token bucket. Name separates the buckets of different route groups.
+// IPLimit is the allowance per client IP; many users can share one address
+// behind a carrier NAT, so it defaults to defaultIPLimitFactor times Limit.
+type RateLimitPolicy struct {
+	Name    string
+	Limit   int
+	IPLimit int
+	Window  time.Duration
+}
+
+const defaultIPLimitFactor = 10
+
+func (p RateLimitPolicy) ipPolicy() RateLimitPolicy {
+	ip := p
+	ip.Limit = p.IPLimit
+	if ip.Limit <= 0 {
+		ip.Limit = p.Limit * defaultIPLimitFactor
+	}
+	return ip
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitBucket names one bucket a request is charged against.
+type RateLimitBucket struct {
+	Key    string
+	Policy RateLimitPolicy
+}
+
+// RateLimitStore keeps bucket state. MemoryRateLimitStore is per-process; a
+// shared implementation (e.g. Redis) can be swapped in for multiple instances.
+// Take charges a request against all buckets at once: it takes a token from
+// each only if every bucket has one, so a denied request costs nothing.
+type RateLimitStore interface {
+	Take(buckets []RateLimitBucket, now time.Time) []RateLimitResult
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type MemoryRateLimitStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(buckets []RateLimitBucket, now time.Time) []RateLimitResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastCleanup) > 10*time.Minute {
+		s.cleanup(now)
+	}
+
+	states := make([]*tokenBucket, len(buckets))
+	allowed := true
+	for i, b := range buckets {
+		limit := float64(b.Policy.Limit)
+		bucket, ok := s.buckets[b.Key]
+		if !ok {
+			bucket = &tokenBucket{tokens: limit, lastSeen: now}
+			s.buckets[b.Key] = bucket
+		} else {
+			elapsed := now.Sub(bucket.lastSeen).Seconds()
+			bucket.tokens = math.Min(limit, bucket.tokens+elapsed*limit/b.Policy.Window.Seconds())
+			bucket.lastSeen = now
+		}
+		states[i] = bucket
+		if bucket.tokens < 1 {
+			allowed = false
+		}
+	}
+
+	results := make([]RateLimitResult, len(buckets))
+	for i, b := range buckets {
+		bucket := states[i]
+		limit := float64(b.Policy.Limit)
+		refillPerSecond := limit / b.Policy.Window.Seconds()
+
+		result := RateLimitResult{Allowed: allowed}
+		if allowed {
+			bucket.tokens--
+		} else if bucket.tokens < 1 {
+			result.RetryAfter = time.Duration((1 - bucket.tokens) / refillPerSecond * float64(time.Second))
+		}
+
+		result.Remaining = int(bucket.tokens)
+		result.Reset = time.Duration((limit - bucket.tokens) / refillPerSecond * float64(time.Second))
+		results[i] = result
+	}
+	return results
+}
+
+// cleanup drops buckets that have been idle long enough to be full again.
+func (s *MemoryRateLimitStore) cleanup(now time.Time) {
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.lastSeen) > time.Hour {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastCleanup = now
+}
+
+// RateLimit applies policy per user when the request is authenticated and
+// per client IP. It must run after the auth middleware for the user bucket
+// to be used. The client IP is only as good as the engine's trusted proxies.
+func RateLimit(store RateLimitStore, policy RateLimitPolicy) gin.HandlerFunc {
+	ipPolicy := policy.ipPolicy()
+
+	return func(c *gin.Context) {
+		now := time.Now()
+
+		var buckets []RateLimitBucket
+		if userID := c.GetUint("user_id"); userID > 0 {
+			buckets = append(buckets, RateLimitBucket{fmt.Sprintf("%s:user:%d", policy.Name, userID), policy})
+		}
+		buckets = append(buckets, RateLimitBucket{fmt.Sprintf("%s:ip:%s", policy.Name, c.ClientIP()), ipPolicy})
+
+		var tightest RateLimitResult
+		limit := policy.Limit
+		for i, result := range store.Take(buckets, now) {
+			if i == 0 || isTighter(result, tightest) {
+				tightest = result
+				limit = buckets[i].Policy.Limit
+			}
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.Reset)))
+
+		if !tightest.Allowed {
+			retryAfter := ceilSeconds(tightest.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many requests, please slow down",
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func isTighter(a, b RateLimitResult) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
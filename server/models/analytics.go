@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+const (
+	AnalyticsPeriodDay  = "day"
+	AnalyticsPeriodWeek = "week"
+)
+
+// AnalyticsStats are the additive counters shared by the room and channel
+// rollups. Averages and rates are derived at read time:
+// avg completion = CompletionSum / CompletionCount, skip rate = Skips / Listens.
+type AnalyticsStats struct {
+	Listens         int64   `gorm:"not null;default:0" json:"listens"`
+	UniqueListeners int64   `gorm:"not null;default:0" json:"unique_listeners"`
+	NewListeners    int64   `gorm:"not null;default:0" json:"new_listeners"`
+	CompletionSum   float64 `gorm:"not null;default:0" json:"-"`
+	CompletionCount int64   `gorm:"not null;default:0" json:"-"`
+	Completions     int64   `gorm:"not null;default:0" json:"completions"`
+	Skips           int64   `gorm:"not null;default:0" json:"skips"`
+	Likes           int64   `gorm:"not null;default:0" json:"likes"`
+	Comments        int64   `gorm:"not null;default:0" json:"comments"`
+	Downloads       int64   `gorm:"not null;default:0" json:"downloads"`
+}
+
+// RoomStat is one day or ISO week (Monday, UTC) of activity on a room.
+type RoomStat struct {
+	RoomID      uint      `gorm:"primaryKey" json:"room_id"`
+	Period      string    `gorm:"primaryKey;size:8" json:"period"`
+	PeriodStart time.Time `gorm:"primaryKey;type:date" json:"period_start"`
+	HostID      uint      `gorm:"not null;index" json:"host_id"`
+	AnalyticsStats
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (RoomStat) TableName() string {
+	return "room_stats"
+}
+
+// ChannelStat aggregates all rooms of a host. UniqueListeners counts distinct
+// listeners across the channel, so it is not the sum of the room values.
+type ChannelStat struct {
+	HostID      uint      `gorm:"primaryKey" json:"host_id"`
+	Period      string    `gorm:"primaryKey;size:8" json:"period"`
+	PeriodStart time.Time `gorm:"primaryKey;type:date" json:"period_start"`
+	AnalyticsStats
+	NewFollowers int64     `gorm:"not null;default:0" json:"new_followers"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (ChannelStat) TableName() string {
+	return "channel_stats"
+}
+
+// AnalyticsPeriodStart returns the UTC start of the day or week containing t.
+func AnalyticsPeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == AnalyticsPeriodWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
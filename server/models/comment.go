@@ -21,6 +21,7 @@ type Comment struct {
 	ParentID      *uint          `gorm:"index" json:"parent_id"`
 	Replies       []Comment      `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
 	LikesCount    int            `gorm:"default:0" json:"likes_count"`
+	Mentions      []Mention      `gorm:"polymorphic:Source;polymorphicValue:comment" json:"mentions,omitempty"`
 }
 
 func (Comment) TableName() string {
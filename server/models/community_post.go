@@ -18,6 +18,7 @@ type CommunityPost struct {
 	Duration      int            `json:"duration"`
 	LikesCount    int            `gorm:"default:0" json:"likes_count"`
 	CommentsCount int            `gorm:"default:0" json:"comments_count"`
+	Mentions      []Mention      `gorm:"polymorphic:Source;polymorphicValue:community_post" json:"mentions,omitempty"`
 }
 
 type CommunityPostImage struct {
@@ -53,6 +54,7 @@ type CommunityPostComment struct {
 	ReplyToUser   *User                  `gorm:"foreignKey:ReplyToUserID" json:"reply_to_user,omitempty"`
 	ParentID      *uint                  `gorm:"index" json:"parent_id"`
 	Replies       []CommunityPostComment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+	Mentions      []Mention              `gorm:"polymorphic:Source;polymorphicValue:community_comment" json:"mentions,omitempty"`
 }
 
 type CommunityCommentLike struct {
@@ -83,19 +85,3 @@ func (CommunityPostComment) TableName() string {
 func (CommunityCommentLike) TableName() string {
 	return "community_comment_likes"
 }
-
-func (CommunityPostLike) AfterMigrate(tx *gorm.DB) error {
-	return tx.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_post_like 
-		ON community_post_likes(community_post_id, user_id) 
-		WHERE deleted_at IS NULL
-	`).Error
-}
-
-func (CommunityCommentLike) AfterMigrate(tx *gorm.DB) error {
-	return tx.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_comment_like 
-		ON community_comment_likes(comment_id, user_id) 
-		WHERE deleted_at IS NULL
-	`).Error
-}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CounterUpdate names a denormalized counter column on a single row.
+type CounterUpdate struct {
+	Table  string
+	ID     uint
+	Column string
+}
+
+// InsertAndIncrement creates row and, only if it did not already exist, bumps
+// every counter by one in the same transaction. It relies on a unique index on
+// the row so concurrent duplicates are rejected by the database.
+func InsertAndIncrement(db *gorm.DB, row interface{}, counters ...CounterUpdate) (bool, error) {
+	created := false
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+
+		return applyCounterDelta(tx, 1, counters)
+	})
+
+	return created, err
+}
+
+// DeleteAndDecrement deletes the rows matched by query and lowers every
+// counter by the number of rows actually deleted, in the same transaction.
+func DeleteAndDecrement(db *gorm.DB, query func(tx *gorm.DB) *gorm.DB, model interface{}, counters ...CounterUpdate) (int64, error) {
+	var deleted int64
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := query(tx).Delete(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleted == 0 {
+			return nil
+		}
+
+		return applyCounterDelta(tx, -deleted, counters)
+	})
+
+	return deleted, err
+}
+
+func applyCounterDelta(tx *gorm.DB, delta int64, counters []CounterUpdate) error {
+	for _, counter := range counters {
+		expr := gorm.Expr(counter.Column+" + ?", delta)
+		if delta < 0 {
+			expr = gorm.Expr("GREATEST("+counter.Column+" + ?, 0)", delta)
+		}
+
+		if err := tx.Table(counter.Table).
+			Where("id = ?", counter.ID).
+			Update(counter.Column, expr).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func AddRoomLike(db *gorm.DB, userID, roomID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&RoomLike{UserID: userID, RoomID: roomID},
+		CounterUpdate{Table: "rooms", ID: roomID, Column: "likes_count"},
+	)
+}
+
+func RemoveRoomLike(db *gorm.DB, userID, roomID uint) (bool, error) {
+	deleted, err := DeleteAndDecrement(db,
+		func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ? AND room_id = ?", userID, roomID) },
+		&RoomLike{},
+		CounterUpdate{Table: "rooms", ID: roomID, Column: "likes_count"},
+	)
+	return deleted > 0, err
+}
+
+func AddFollow(db *gorm.DB, followerID, followingID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&Follow{FollowerID: followerID, FollowingID: followingID},
+		CounterUpdate{Table: "users", ID: followerID, Column: "following_count"},
+		CounterUpdate{Table: "users", ID: followingID, Column: "followers_count"},
+	)
+}
+
+func RemoveFollow(db *gorm.DB, followerID, followingID uint) (bool, error) {
+	deleted, err := DeleteAndDecrement(db,
+		func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("follower_id = ? AND following_id = ?", followerID, followingID)
+		},
+		&Follow{},
+		CounterUpdate{Table: "users", ID: followerID, Column: "following_count"},
+		CounterUpdate{Table: "users", ID: followingID, Column: "followers_count"},
+	)
+	return deleted > 0, err
+}
+
+func AddCommentLike(db *gorm.DB, userID, commentID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&CommentLike{UserID: userID, CommentID: commentID},
+		CounterUpdate{Table: "comments", ID: commentID, Column: "likes_count"},
+	)
+}
+
+func RemoveCommentLike(db *gorm.DB, userID, commentID uint) (bool, error) {
+	deleted, err := DeleteAndDecrement(db,
+		func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ? AND comment_id = ?", userID, commentID) },
+		&CommentLike{},
+		CounterUpdate{Table: "comments", ID: commentID, Column: "likes_count"},
+	)
+	return deleted > 0, err
+}
+
+func AddCommunityPostLike(db *gorm.DB, userID, postID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&CommunityPostLike{UserID: userID, CommunityPostID: postID},
+		CounterUpdate{Table: "community_posts", ID: postID, Column: "likes_count"},
+	)
+}
+
+func RemoveCommunityPostLike(db *gorm.DB, userID, postID uint) (bool, error) {
+	deleted, err := DeleteAndDecrement(db,
+		func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("user_id = ? AND community_post_id = ?", userID, postID)
+		},
+		&CommunityPostLike{},
+		CounterUpdate{Table: "community_posts", ID: postID, Column: "likes_count"},
+	)
+	return deleted > 0, err
+}
+
+func AddCommunityCommentLike(db *gorm.DB, userID, commentID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&CommunityCommentLike{UserID: userID, CommentID: commentID},
+		CounterUpdate{Table: "community_post_comments", ID: commentID, Column: "likes_count"},
+	)
+}
+
+func RemoveCommunityCommentLike(db *gorm.DB, userID, commentID uint) (bool, error) {
+	deleted, err := DeleteAndDecrement(db,
+		func(tx *gorm.DB) *gorm.DB { return tx.Where("user_id = ? AND comment_id = ?", userID, commentID) },
+		&CommunityCommentLike{},
+		CounterUpdate{Table: "community_post_comments", ID: commentID, Column: "likes_count"},
+	)
+	return deleted > 0, err
+}
+
+func RecordUniqueListen(db *gorm.DB, userID, roomID uint) (bool, error) {
+	return InsertAndIncrement(db,
+		&UniqueRoomListen{UserID: userID, RoomID: roomID},
+		CounterUpdate{Table: "rooms", ID: roomID, Column: "total_listens"},
+	)
+}
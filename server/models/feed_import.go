@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+const (
+	FeedImportStatusPending   = "pending"
+	FeedImportStatusRunning   = "running"
+	FeedImportStatusCompleted = "completed"
+	FeedImportStatusFailed    = "failed"
+)
+
+const (
+	FeedImportItemPending  = "pending"
+	FeedImportItemImported = "imported"
+	FeedImportItemSkipped  = "skipped"
+	FeedImportItemFailed   = "failed"
+)
+
+// FeedImport is a job that turns the episodes of an external podcast feed
+// into rooms owned by UserID.
+type FeedImport struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	SourceURL string `gorm:"size:2048" json:"source_url,omitempty"`
+	FeedTitle string `json:"feed_title"`
+	Topic     string `gorm:"not null" json:"topic"`
+	TopicID   uint   `gorm:"not null" json:"topic_id"`
+	IsPrivate bool   `gorm:"default:false" json:"is_private"`
+	Status    string `gorm:"size:16;not null;default:pending;index" json:"status"`
+	Error     string `gorm:"type:text" json:"error,omitempty"`
+
+	TotalItems    int `gorm:"default:0" json:"total_items"`
+	ImportedItems int `gorm:"default:0" json:"imported_items"`
+	SkippedItems  int `gorm:"default:0" json:"skipped_items"`
+	FailedItems   int `gorm:"default:0" json:"failed_items"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// FeedImportItem is one episode of an import. GUID is the feed's item GUID
+// (or the enclosure URL when the feed has none) and is kept on the room so
+// later imports skip it.
+type FeedImportItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ImportID    uint       `gorm:"not null;index" json:"import_id"`
+	GUID        string     `gorm:"size:512;not null" json:"guid"`
+	Title       string     `json:"title"`
+	Description string     `gorm:"type:text" json:"description"`
+	AudioURL    string     `gorm:"size:2048" json:"audio_url"`
+	ImageURL    string     `gorm:"size:2048" json:"image_url,omitempty"`
+	Duration    int        `json:"duration"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+
+	Status   string `gorm:"size:16;not null;default:pending;index" json:"status"`
+	Attempts int    `gorm:"default:0" json:"attempts"`
+	Error    string `gorm:"type:text" json:"error,omitempty"`
+	RoomID   *uint  `json:"room_id,omitempty"`
+}
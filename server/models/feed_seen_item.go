@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FeedItemRoom          = "room"
+	FeedItemCommunityPost = "community_post"
+	FeedItemLikedRoom     = "liked_room"
+)
+
+// FeedSeenItemRetention bounds how long the home feed remembers what a user
+// has already seen; the feed itself only looks back FeedWindow.
+const (
+	FeedWindow            = 30 * 24 * time.Hour
+	FeedSeenItemRetention = FeedWindow
+)
+
+// FeedSeenItem marks a home feed item as already seen by a user. Liked-room
+// activity is tracked under the room it points at.
+type FeedSeenItem struct {
+	ID       uint      `gorm:"primarykey" json:"id"`
+	SeenAt   time.Time `gorm:"not null;index" json:"seen_at"`
+	UserID   uint      `gorm:"not null;uniqueIndex:idx_feed_seen_user_item" json:"user_id"`
+	ItemType string    `gorm:"size:30;not null;uniqueIndex:idx_feed_seen_user_item" json:"item_type"`
+	ItemID   uint      `gorm:"not null;uniqueIndex:idx_feed_seen_user_item" json:"item_id"`
+}
+
+func (FeedSeenItem) TableName() string {
+	return "feed_seen_items"
+}
+
+func DeleteOldFeedSeenItems(db *gorm.DB, olderThan time.Time) (int64, error) {
+	result := db.Where("seen_at < ?", olderThan).Delete(&FeedSeenItem{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// WalletKindCoins holds coins a user bought and can spend on gifts.
+	WalletKindCoins = "coins"
+	// WalletKindEarnings holds the value of gifts a user received. Its
+	// ledger sum is User.TotalGiftsValue.
+	WalletKindEarnings = "earnings"
+	// WalletKindIssuance is the single system wallet purchased coins are
+	// drawn from. Its balance is minus the number of coins in circulation.
+	WalletKindIssuance = "issuance"
+)
+
+const (
+	LedgerKindPurchase       = "purchase"
+	LedgerKindGift           = "gift"
+	LedgerKindGiftRefund     = "gift_refund"
+	LedgerKindPurchaseRefund = "purchase_refund"
+)
+
+const (
+	GiftTargetRoom          = "room"
+	GiftTargetCommunityPost = "community_post"
+)
+
+var ErrLedgerImmutable = errors.New("ledger records cannot be modified")
+
+type GiftType struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Slug      string    `gorm:"size:64;uniqueIndex;not null" json:"slug"`
+	Name      string    `gorm:"size:64;not null" json:"name"`
+	IconURL   string    `json:"icon_url"`
+	CoinPrice int64     `gorm:"not null" json:"coin_price"`
+	Position  int       `gorm:"default:0" json:"position"`
+	IsActive  bool      `gorm:"default:true;index" json:"is_active"`
+}
+
+func (GiftType) TableName() string {
+	return "gift_types"
+}
+
+// Wallet is an account in the coin ledger. Balance is a cache of the sum of
+// the wallet's ledger entries and is only changed together with an entry.
+type Wallet struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    *uint     `gorm:"uniqueIndex:idx_wallet_owner_kind" json:"user_id"`
+	Kind      string    `gorm:"size:16;not null;uniqueIndex:idx_wallet_owner_kind" json:"kind"`
+	Balance   int64     `gorm:"not null;default:0" json:"balance"`
+}
+
+func (Wallet) TableName() string {
+	return "wallets"
+}
+
+// LedgerTransaction groups the entries of one money movement. Entries of a
+// transaction always sum to zero. Reference makes retries idempotent.
+type LedgerTransaction struct {
+	ID          uint          `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Kind        string        `gorm:"size:32;not null;index" json:"kind"`
+	Reference   string        `gorm:"size:128;uniqueIndex;not null" json:"reference"`
+	InitiatorID uint          `gorm:"index" json:"initiator_id"`
+	ReversesID  *uint         `gorm:"uniqueIndex" json:"reverses_id,omitempty"`
+	Memo        string        `json:"memo,omitempty"`
+	Entries     []LedgerEntry `gorm:"foreignKey:TransactionID" json:"entries,omitempty"`
+}
+
+func (LedgerTransaction) TableName() string {
+	return "ledger_transactions"
+}
+
+func (LedgerTransaction) BeforeUpdate(tx *gorm.DB) error { return ErrLedgerImmutable }
+func (LedgerTransaction) BeforeDelete(tx *gorm.DB) error { return ErrLedgerImmutable }
+
+// LedgerEntry is one side of a transaction: positive amounts credit the
+// wallet, negative amounts debit it.
+type LedgerEntry struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	TransactionID uint      `gorm:"not null;index" json:"transaction_id"`
+	WalletID      uint      `gorm:"not null;index" json:"wallet_id"`
+	Amount        int64     `gorm:"not null" json:"amount"`
+}
+
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+func (LedgerEntry) BeforeUpdate(tx *gorm.DB) error { return ErrLedgerImmutable }
+func (LedgerEntry) BeforeDelete(tx *gorm.DB) error { return ErrLedgerImmutable }
+
+type Gift struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SenderID      uint       `gorm:"not null;index" json:"sender_id"`
+	Sender        User       `gorm:"foreignKey:SenderID" json:"sender"`
+	RecipientID   uint       `gorm:"not null;index" json:"recipient_id"`
+	GiftTypeID    uint       `gorm:"not null" json:"gift_type_id"`
+	GiftType      GiftType   `gorm:"foreignKey:GiftTypeID" json:"gift_type"`
+	TargetType    string     `gorm:"size:32;not null;index:idx_gift_target" json:"target_type"`
+	TargetID      uint       `gorm:"not null;index:idx_gift_target" json:"target_id"`
+	Quantity      int        `gorm:"not null" json:"quantity"`
+	CoinAmount    int64      `gorm:"not null" json:"coin_amount"`
+	Message       string     `json:"message,omitempty"`
+	TransactionID uint       `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	RefundedAt    *time.Time `json:"refunded_at,omitempty"`
+}
+
+func (Gift) TableName() string {
+	return "gifts"
+}
+
+// DefaultGiftTypes seeds the catalogue on first run.
+var DefaultGiftTypes = []GiftType{
+	{Slug: "heart", Name: "Heart", CoinPrice: 1, Position: 1},
+	{Slug: "coffee", Name: "Coffee", CoinPrice: 10, Position: 2},
+	{Slug: "microphone", Name: "Microphone", CoinPrice: 50, Position: 3},
+	{Slug: "trophy", Name: "Trophy", CoinPrice: 200, Position: 4},
+	{Slug: "rocket", Name: "Rocket", CoinPrice: 1000, Position: 5},
+}
+
+func SeedGiftTypes(db *gorm.DB) error {
+	defaults := make([]GiftType, len(DefaultGiftTypes))
+	copy(defaults, DefaultGiftTypes)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaults).Error
+}
+
+// SeedIssuanceWallet makes sure exactly one issuance wallet exists. The
+// (user_id, kind) index treats NULL owners as distinct, so system wallets get
+// their own partial index. Duplicates created before it existed are merged
+// into the oldest one first.
+func SeedIssuanceWallet(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&Wallet{}).
+			Where("user_id IS NULL AND kind = ?", WalletKindIssuance).
+			Order("id ASC").
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 1 {
+			keep, extra := ids[0], ids[1:]
+			if err := tx.Exec("UPDATE ledger_entries SET wallet_id = ? WHERE wallet_id IN ?", keep, extra).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE wallets SET balance = (SELECT SUM(balance) FROM wallets WHERE id IN ?) WHERE id = ?", ids, keep).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", extra).Delete(&Wallet{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(`
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_system_kind
+			ON wallets(kind)
+			WHERE user_id IS NULL
+		`).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Wallet{Kind: WalletKindIssuance}).Error
+	})
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	MaxHashtagLength   = 50
+	MaxHashtagsPerItem = 30
+)
+
+// Hashtag names are stored normalized (lowercase, without the leading #).
+type Hashtag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Name      string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+}
+
+func (Hashtag) TableName() string {
+	return "hashtags"
+}
+
+type RoomHashtag struct {
+	RoomID    uint      `gorm:"primaryKey" json:"room_id"`
+	HashtagID uint      `gorm:"primaryKey;index" json:"hashtag_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (RoomHashtag) TableName() string {
+	return "room_hashtags"
+}
+
+type CommunityPostHashtag struct {
+	CommunityPostID uint      `gorm:"primaryKey" json:"community_post_id"`
+	HashtagID       uint      `gorm:"primaryKey;index" json:"hashtag_id"`
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
+
+func (CommunityPostHashtag) TableName() string {
+	return "community_post_hashtags"
+}
+
+// A tag starts after whitespace, punctuation or the start of the text, so
+// URL fragments ("page#section") and HTML entities ("&#39;") are skipped.
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#/])#([\p{L}\p{N}_]+)`)
+
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// ExtractHashtags returns the distinct normalized hashtags in text, in order
+// of first appearance. Purely numeric tags ("#1") are not hashtags.
+func ExtractHashtags(text string) []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		tag := NormalizeHashtag(match[1])
+		if len([]rune(tag)) > MaxHashtagLength || seen[tag] || !strings.ContainsFunc(tag, unicode.IsLetter) {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == MaxHashtagsPerItem {
+			break
+		}
+	}
+	return tags
+}
+
+// SyncRoomHashtags makes the room's tag links match the hashtags in text,
+// adding new links and removing ones no longer present.
+func SyncRoomHashtags(tx *gorm.DB, roomID uint, text string) error {
+	return syncHashtagLinks(tx, "room_hashtags", "room_id", roomID, text)
+}
+
+func SyncCommunityPostHashtags(tx *gorm.DB, postID uint, text string) error {
+	return syncHashtagLinks(tx, "community_post_hashtags", "community_post_id", postID, text)
+}
+
+func syncHashtagLinks(tx *gorm.DB, table, column string, itemID uint, text string) error {
+	names := ExtractHashtags(text)
+
+	var tagIDs []uint
+	if len(names) > 0 {
+		hashtags := make([]Hashtag, 0, len(names))
+		for _, name := range names {
+			hashtags = append(hashtags, Hashtag{Name: name})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&hashtags).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Hashtag{}).Where("name IN ?", names).Pluck("id", &tagIDs).Error; err != nil {
+			return err
+		}
+	}
+
+	stale := tx.Table(table).Where(column+" = ?", itemID)
+	if len(tagIDs) > 0 {
+		stale = stale.Where("hashtag_id NOT IN ?", tagIDs)
+	}
+	if err := stale.Delete(nil).Error; err != nil {
+		return err
+	}
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	links := make([]map[string]interface{}, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		links = append(links, map[string]interface{}{
+			column:       itemID,
+			"hashtag_id": tagID,
+			"created_at": now,
+		})
+	}
+	return tx.Table(table).Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// BackfillHashtags links rooms and posts written before hashtags were parsed.
+// Only items containing '#' and without any tag links are visited, so it is
+// cheap to run on every start.
+func BackfillHashtags(db *gorm.DB) (int, error) {
+	processed := 0
+
+	var rooms []Room
+	if err := db.Select("id, description").
+		Where("description LIKE ?", "%#%").
+		Where("NOT EXISTS (SELECT 1 FROM room_hashtags rh WHERE rh.room_id = rooms.id)").
+		Find(&rooms).Error; err != nil {
+		return processed, err
+	}
+	for _, room := range rooms {
+		if err := SyncRoomHashtags(db, room.ID, room.Description); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	var posts []CommunityPost
+	if err := db.Select("id, content").
+		Where("content LIKE ?", "%#%").
+		Where("NOT EXISTS (SELECT 1 FROM community_post_hashtags ph WHERE ph.community_post_id = community_posts.id)").
+		Find(&posts).Error; err != nil {
+		return processed, err
+	}
+	for _, post := range posts {
+		if err := SyncCommunityPostHashtags(db, post.ID, post.Content); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	return processed, nil
+}
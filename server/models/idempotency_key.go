@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyKey struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID uint   `gorm:"not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	Key    string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key" json:"key"`
+
+	Method      string `gorm:"type:varchar(10);not null" json:"method"`
+	Path        string `gorm:"not null" json:"path"`
+	RequestHash string `gorm:"type:char(64);not null" json:"request_hash"`
+
+	StatusCode   int        `json:"status_code"`
+	ContentType  string     `json:"content_type"`
+	ResponseBody []byte     `json:"-"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+func DeleteExpiredIdempotencyKeys(tx *gorm.DB, olderThan time.Time) (int64, error) {
+	result := tx.Where("created_at < ?", olderThan).Delete(&IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MentionSourceComment          = "comment"
+	MentionSourceCommunityPost    = "community_post"
+	MentionSourceCommunityComment = "community_comment"
+
+	MaxMentionsPerItem = 20
+)
+
+// Mention records an @username in a comment or post. Offset and Length are
+// in UTF-16 code units of the source text, the unit the mobile and web
+// clients index strings by, and cover the leading '@'.
+type Mention struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	SourceType      string    `gorm:"size:32;not null;index:idx_mention_source" json:"source_type"`
+	SourceID        uint      `gorm:"not null;index:idx_mention_source" json:"source_id"`
+	AuthorID        uint      `gorm:"not null;index" json:"author_id"`
+	MentionedUserID uint      `gorm:"not null;index" json:"user_id"`
+	Username        string    `json:"username"`
+	Offset          int       `gorm:"column:start_offset" json:"offset"`
+	Length          int       `json:"length"`
+}
+
+func (Mention) TableName() string {
+	return "mentions"
+}
+
+type ParsedMention struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])(@[A-Za-z0-9_.]{3,30})`)
+
+// ParseMentions finds @username tokens in text. Usernames are lowercased; a
+// trailing '.' is treated as punctuation rather than part of the name.
+func ParseMentions(text string) []ParsedMention {
+	mentions := []ParsedMention{}
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		token := strings.TrimRight(text[start:end], ".")
+		if len(token) < 4 {
+			continue
+		}
+		mentions = append(mentions, ParsedMention{
+			Username: strings.ToLower(token[1:]),
+			Offset:   utf16Len(text[:start]),
+			Length:   utf16Len(token),
+		})
+	}
+	return mentions
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// SyncMentions replaces the mention records of a source with the mentions in
+// text that resolve to active users. It returns the records for users who were
+// not mentioned in the previous version, so edits only notify new mentions.
+func SyncMentions(tx *gorm.DB, sourceType string, sourceID, authorID uint, text string) ([]Mention, error) {
+	parsed := ParseMentions(text)
+
+	var previousUserIDs []uint
+	if err := tx.Model(&Mention{}).
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Pluck("mentioned_user_id", &previousUserIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Delete(&Mention{}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	usernames := make([]string, 0, len(parsed))
+	for _, m := range parsed {
+		usernames = append(usernames, m.Username)
+	}
+
+	var users []User
+	if err := tx.Select("id, username").
+		Where("LOWER(username) IN ? AND is_active = ?", usernames, true).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	userIDs := make(map[string]uint, len(users))
+	for _, user := range users {
+		userIDs[strings.ToLower(user.Username)] = user.ID
+	}
+
+	mentions := []Mention{}
+	distinctUsers := map[uint]bool{}
+	for _, m := range parsed {
+		userID, ok := userIDs[m.Username]
+		if !ok {
+			continue
+		}
+		if !distinctUsers[userID] && len(distinctUsers) == MaxMentionsPerItem {
+			continue
+		}
+		distinctUsers[userID] = true
+		mentions = append(mentions, Mention{
+			SourceType:      sourceType,
+			SourceID:        sourceID,
+			AuthorID:        authorID,
+			MentionedUserID: userID,
+			Username:        m.Username,
+			Offset:          m.Offset,
+			Length:          m.Length,
+		})
+	}
+
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	if err := tx.Create(&mentions).Error; err != nil {
+		return nil, err
+	}
+
+	previous := make(map[uint]bool, len(previousUserIDs))
+	for _, id := range previousUserIDs {
+		previous[id] = true
+	}
+	added := []Mention{}
+	for _, mention := range mentions {
+		if !previous[mention.MentionedUserID] {
+			added = append(added, mention)
+		}
+	}
+	return added, nil
+}
+
+// LoadMentions returns the mentions of the given sources keyed by source ID,
+// ordered by position in the text.
+func LoadMentions(db *gorm.DB, sourceType string, sourceIDs []uint) map[uint][]Mention {
+	result := map[uint][]Mention{}
+	if len(sourceIDs) == 0 {
+		return result
+	}
+
+	var mentions []Mention
+	db.Where("source_type = ? AND source_id IN ?", sourceType, sourceIDs).
+		Order("source_id, start_offset").
+		Find(&mentions)
+	for _, mention := range mentions {
+		result[mention.SourceID] = append(result[mention.SourceID], mention)
+	}
+	return result
+}
@@ -14,12 +14,14 @@ const (
 	NotificationTypeComment        NotificationType = "comment"
 	NotificationTypeCommentLike    NotificationType = "comment_like"
 	NotificationTypeNewRoom        NotificationType = "new_room"
+	NotificationTypeNewEpisode     NotificationType = "new_episode"
 	NotificationTypeRoomLive       NotificationType = "room_live"
 	NotificationTypeCommunityPost  NotificationType = "community_post"
 	NotificationTypeCommunityLike  NotificationType = "community_post_like"
 	NotificationTypeCommunityComment NotificationType = "community_comment"
 	NotificationTypeGift           NotificationType = "gift"
 	NotificationTypeMention        NotificationType = "mention"
+	NotificationTypeSavedSearch    NotificationType = "saved_search"
 	NotificationTypeSystem         NotificationType = "system"
 )
 
@@ -73,3 +75,12 @@ func GetUnreadCount(tx *gorm.DB, userID uint) (int64, error) {
 		Count(&count).Error
 	return count, err
 }
+
+// CountNotificationsSince counts notifications of type sent to userID after since.
+func CountNotificationsSince(db *gorm.DB, userID uint, notificationType NotificationType, since time.Time) (int64, error) {
+	var count int64
+	err := db.Model(&Notification{}).
+		Where("user_id = ? AND type = ? AND created_at > ?", userID, notificationType, since).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PlaybackState is a user's resume point in a room, shared across devices.
+// UpdatedAt is the client's write time and decides conflicts: the newest
+// write wins, older ones are dropped.
+type PlaybackState struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;index:idx_playback_state_user_updated,priority:2" json:"updated_at"`
+
+	UserID uint `gorm:"not null;uniqueIndex:idx_playback_state_user_room;index:idx_playback_state_user_updated,priority:1" json:"user_id"`
+	RoomID uint `gorm:"not null;uniqueIndex:idx_playback_state_user_room" json:"room_id"`
+	Room   Room `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+
+	Position   int    `gorm:"not null;default:0" json:"position"`
+	Device     string `gorm:"size:100" json:"device"`
+	IsFinished bool   `gorm:"not null;default:false" json:"finished"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PlaylistVisibilityPublic  = "public"
+	PlaylistVisibilityPrivate = "private"
+)
+
+// MaxPlaylistItems bounds a playlist so reorders can renumber every item.
+const MaxPlaylistItems = 500
+
+type Playlist struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	OwnerID     uint   `gorm:"not null;index" json:"owner_id"`
+	Owner       User   `gorm:"foreignKey:OwnerID" json:"owner"`
+	Title       string `gorm:"size:120;not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	CoverURL    string `json:"cover_url"`
+	Visibility  string `gorm:"size:16;not null;default:public;index" json:"visibility"`
+
+	// ShareToken grants read access to anyone holding the link, including
+	// for private playlists. Nil when sharing is off.
+	ShareToken *string `gorm:"size:64;uniqueIndex" json:"-"`
+
+	ItemsCount     int `gorm:"default:0" json:"items_count"`
+	FollowersCount int `gorm:"default:0" json:"followers_count"`
+}
+
+// PlaylistItem is a room in a playlist. Positions are contiguous from 0 and
+// renumbered on every move, so the order is always total and stable.
+type PlaylistItem struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	PlaylistID uint      `gorm:"not null;uniqueIndex:idx_playlist_item_room;index:idx_playlist_item_position" json:"playlist_id"`
+	RoomID     uint      `gorm:"not null;uniqueIndex:idx_playlist_item_room;index" json:"room_id"`
+	Room       Room      `gorm:"foreignKey:RoomID" json:"room"`
+	AddedByID  uint      `gorm:"not null" json:"added_by_id"`
+	Position   int       `gorm:"not null;index:idx_playlist_item_position" json:"position"`
+}
+
+// PlaylistCollaborator can add rooms to someone else's playlist.
+type PlaylistCollaborator struct {
+	PlaylistID uint      `gorm:"primaryKey;autoIncrement:false" json:"playlist_id"`
+	UserID     uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	User       User      `gorm:"foreignKey:UserID" json:"user"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PlaylistFollow struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	PlaylistID uint      `gorm:"not null;uniqueIndex:idx_playlist_follow" json:"playlist_id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_playlist_follow;index" json:"user_id"`
+}
+
+// IsPlaylistCollaborator reports whether userID may add items to the playlist.
+func IsPlaylistCollaborator(db *gorm.DB, playlistID, userID uint) (bool, error) {
+	var count int64
+	err := db.Model(&PlaylistCollaborator{}).
+		Where("playlist_id = ? AND user_id = ?", playlistID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
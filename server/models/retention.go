@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// RetentionBucketSeconds is the width of one retention histogram bucket.
+const RetentionBucketSeconds = 5
+
+const (
+	PlaybackEventPosition = "position"
+	PlaybackEventSeek     = "seek"
+)
+
+// PlaybackSession remembers the last playback ping of a listen so the next
+// batch can be stitched onto it.
+type PlaybackSession struct {
+	HistoryID    uint      `gorm:"primaryKey;autoIncrement:false" json:"history_id"`
+	RoomID       uint      `gorm:"not null;index" json:"room_id"`
+	UserID       uint      `gorm:"not null" json:"user_id"`
+	LastPosition int       `gorm:"not null" json:"last_position"`
+	LastEventAt  time.Time `gorm:"not null" json:"last_event_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// RetentionCoverage marks a bucket of a room as heard during one listen.
+// Listeners still present at a bucket is the number of listens covering it.
+type RetentionCoverage struct {
+	HistoryID uint `gorm:"primaryKey;autoIncrement:false"`
+	Bucket    int  `gorm:"primaryKey;autoIncrement:false;index:idx_retention_coverage_room_bucket,priority:2"`
+	RoomID    uint `gorm:"not null;index:idx_retention_coverage_room_bucket,priority:1"`
+}
+
+func (RetentionCoverage) TableName() string {
+	return "retention_coverage"
+}
+
+// RetentionHotspot counts seeks over a bucket: replays are backward seeks
+// across it, skips are forward seeks across it.
+type RetentionHotspot struct {
+	RoomID  uint  `gorm:"primaryKey;autoIncrement:false" json:"room_id"`
+	Bucket  int   `gorm:"primaryKey;autoIncrement:false" json:"bucket"`
+	Replays int64 `gorm:"not null;default:0" json:"replays"`
+	Skips   int64 `gorm:"not null;default:0" json:"skips"`
+}
+
+// RetentionBucket returns the histogram bucket containing position seconds.
+func RetentionBucket(position int) int {
+	if position < 0 {
+		return 0
+	}
+	return position / RetentionBucketSeconds
+}
@@ -0,0 +1,50 @@
+package models
+
+type Permission string
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	PermissionReportsRead    Permission = "reports:read"
+	PermissionRoomsModerate  Permission = "rooms:moderate"
+	PermissionUsersBan       Permission = "users:ban"
+	PermissionRolesGrant     Permission = "roles:grant"
+	PermissionSystemMaintain Permission = "system:maintain"
+	PermissionTopicsManage   Permission = "topics:manage"
+	PermissionGiftsManage    Permission = "gifts:manage"
+)
+
+var RolePermissions = map[string][]Permission{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionReportsRead,
+		PermissionRoomsModerate,
+	},
+	RoleAdmin: {
+		PermissionReportsRead,
+		PermissionRoomsModerate,
+		PermissionUsersBan,
+		PermissionRolesGrant,
+		PermissionSystemMaintain,
+		PermissionTopicsManage,
+		PermissionGiftsManage,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -14,10 +14,11 @@ type Room struct {
 	Title         string         `gorm:"not null" json:"title"`
 	Description   string         `json:"description"`
 	Topic         string         `gorm:"not null" json:"topic"`
+	TopicID       *uint          `gorm:"index" json:"topic_id"`
 	AudioURL      string         `gorm:"not null" json:"audio_url"`
 	ThumbnailURL  string         `json:"thumbnail_url"`
 	Duration      int            `json:"duration"`
-	HostID        uint           `gorm:"not null" json:"host_id"`
+	HostID        uint           `gorm:"not null;uniqueIndex:idx_room_host_external_guid,priority:1,where:deleted_at IS NULL" json:"host_id"`
 	Host          User           `gorm:"foreignKey:HostID" json:"host"`
 	IsLive        bool           `gorm:"default:false" json:"is_live"`
 	IsPrivate     bool           `gorm:"default:false" json:"is_private"`
@@ -28,12 +29,54 @@ type Room struct {
 	IsHidden      bool           `gorm:"default:false" json:"is_hidden"`
 	HiddenAt      *time.Time     `json:"hidden_at,omitempty"`
 	HiddenReason  string         `json:"hidden_reason,omitempty"`
+	TrendingScore float64        `gorm:"default:0" json:"trending_score"`
+	WeeklyListens int            `gorm:"default:0" json:"weekly_listens"`
+	SeriesID      *uint          `gorm:"index:idx_room_series_episode,priority:1" json:"series_id,omitempty"`
+	SeasonNumber  *int           `gorm:"index:idx_room_series_episode,priority:2" json:"season_number,omitempty"`
+	EpisodeNumber *int           `gorm:"index:idx_room_series_episode,priority:3" json:"episode_number,omitempty"`
+	ExternalGUID  *string        `gorm:"size:512;index;uniqueIndex:idx_room_host_external_guid,priority:2" json:"-"`
+	Status        string         `gorm:"size:16;not null;default:published;index" json:"status"`
+	PublishAt     *time.Time     `gorm:"index" json:"publish_at,omitempty"`
+	// PublishedAt is when the room actually went public. Publishing moves
+	// CreatedAt back to the scheduled time, so "new since" checks use this.
+	PublishedAt *time.Time `gorm:"index" json:"published_at,omitempty"`
 }
 
+// Only published rooms appear anywhere but the host's own views. Scheduled
+// rooms are published by the scheduler once PublishAt passes.
+const (
+	RoomStatusDraft     = "draft"
+	RoomStatusScheduled = "scheduled"
+	RoomStatusPublished = "published"
+)
+
 func (Room) TableName() string {
 	return "rooms"
 }
 
+func (r *Room) BeforeCreate(tx *gorm.DB) error {
+	if r.PublishedAt == nil && (r.Status == "" || r.Status == RoomStatusPublished) {
+		now := time.Now()
+		r.PublishedAt = &now
+	}
+	return nil
+}
+
+// BackfillRoomPublishedAt sets PublishedAt on rooms published before the
+// column existed.
+func BackfillRoomPublishedAt(db *gorm.DB) (int64, error) {
+	result := db.Model(&Room{}).
+		Where("published_at IS NULL AND status = ?", RoomStatusPublished).
+		UpdateColumn("published_at", gorm.Expr("created_at"))
+	return result.RowsAffected, result.Error
+}
+
 func (r *Room) IncrementListens(tx *gorm.DB) error {
 	return tx.Model(r).Update("total_listens", gorm.Expr("total_listens + ?", 1)).Error
 }
+
+// IsVisibleTo reports whether userID may open the room. Unpublished rooms
+// are only visible to their host.
+func (r *Room) IsVisibleTo(userID uint) bool {
+	return r.Status == RoomStatusPublished || r.HostID == userID
+}
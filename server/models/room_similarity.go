@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RoomSimilarity is one precomputed item-item neighbour: listeners who engaged
+// with RoomID also engaged with SimilarRoomID. Rows are rebuilt wholesale by
+// the recommendation job.
+type RoomSimilarity struct {
+	RoomID        uint      `gorm:"primaryKey;autoIncrement:false" json:"room_id"`
+	SimilarRoomID uint      `gorm:"primaryKey;autoIncrement:false" json:"similar_room_id"`
+	Score         float64   `gorm:"not null" json:"score"`
+	CoUsers       int       `gorm:"not null" json:"co_users"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (RoomSimilarity) TableName() string {
+	return "room_similarities"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SavedSearchFrequencyHourly = "hourly"
+	SavedSearchFrequencyDaily  = "daily"
+	SavedSearchFrequencyWeekly = "weekly"
+)
+
+// SavedSearchFrequencies maps each alert frequency to how often it runs.
+var SavedSearchFrequencies = map[string]time.Duration{
+	SavedSearchFrequencyHourly: time.Hour,
+	SavedSearchFrequencyDaily:  24 * time.Hour,
+	SavedSearchFrequencyWeekly: 7 * 24 * time.Hour,
+}
+
+// SavedSearch is a query plus filters that a user wants to be alerted about.
+// Filters holds the filter query parameters in URL-encoded form, the same
+// ones accepted by the search endpoints.
+type SavedSearch struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID        uint           `gorm:"not null;index" json:"user_id"`
+	Name          string         `gorm:"size:100;not null" json:"name"`
+	Query         string         `gorm:"size:100;not null" json:"query"`
+	Filters       string         `gorm:"type:text" json:"-"`
+	Frequency     string         `gorm:"size:20;not null;default:'daily'" json:"frequency"`
+	AlertsEnabled bool           `gorm:"not null;default:true" json:"alerts_enabled"`
+	LastRunAt     time.Time      `gorm:"not null" json:"last_run_at"`
+	NextRunAt     time.Time      `gorm:"not null;index" json:"next_run_at"`
+}
+
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+func IsValidSavedSearchFrequency(frequency string) bool {
+	_, ok := SavedSearchFrequencies[frequency]
+	return ok
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// CreateSearchIndexes adds the generated tsvector columns and the GIN indexes
+// used by full-text and trigram search. For rooms, title outranks topic, which
+// outranks description; posts and comments are indexed on their content.
+func CreateSearchIndexes(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+
+		`ALTER TABLE rooms ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(topic, '')), 'B') ||
+				setweight(to_tsvector('english', coalesce(description, '')), 'C')
+			) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_search_vector ON rooms USING GIN (search_vector)`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_title_trgm ON rooms USING GIN (title gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_topic_trgm ON rooms USING GIN (topic gin_trgm_ops)`,
+
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(username, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(full_name, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(bio, '')), 'C')
+			) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (username gin_trgm_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_full_name_trgm ON users USING GIN (full_name gin_trgm_ops)`,
+
+		`ALTER TABLE community_posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_community_posts_search_vector ON community_posts USING GIN (search_vector)`,
+
+		`ALTER TABLE community_post_comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_community_post_comments_search_vector ON community_post_comments USING GIN (search_vector)`,
+
+		`ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_comments_search_vector ON comments USING GIN (search_vector)`,
+
+		// Prefix lookups for type-ahead suggestions.
+		`CREATE INDEX IF NOT EXISTS idx_users_username_prefix ON users (LOWER(username) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_title_prefix ON rooms (LOWER(title) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_topic_prefix ON rooms (LOWER(topic) text_pattern_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_search_query_logs_query_trgm ON search_query_logs USING GIN (query gin_trgm_ops)`,
+
+		// Search filters and sort modes only ever look at public, visible rooms.
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_topic_created ON rooms (topic, created_at DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_created ON rooms (created_at DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_likes ON rooms (likes_count DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_listens ON rooms (total_listens DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_duration ON rooms (duration)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_trending ON rooms (trending_score DESC, id DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_public_weekly_listens ON rooms (weekly_listens DESC, id DESC)
+			WHERE deleted_at IS NULL AND is_private = false AND is_hidden = false`,
+		`CREATE INDEX IF NOT EXISTS idx_rooms_host_id ON rooms (host_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_verified ON users (id) WHERE is_verified = true`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchQueryLogRetention bounds how long anonymous query logs are kept for
+// trending searches and spelling suggestions.
+const SearchQueryLogRetention = 30 * 24 * time.Hour
+
+// SearchQueryLog records a search without anything identifying who ran it.
+// SearcherHash hashes the user or client IP with that day's salt, so it only
+// tells searchers apart within a day and can't link one user's queries
+// across days.
+type SearchQueryLog struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+	Query        string    `gorm:"size:100;not null;index" json:"query"`
+	ResultCount  int       `gorm:"not null;default:0" json:"result_count"`
+	SearcherHash string    `gorm:"size:64" json:"-"`
+}
+
+func (SearchQueryLog) TableName() string {
+	return "search_query_logs"
+}
+
+// SearchQuerySalt is the random salt for one UTC day of searcher hashes.
+// Salts of past days are deleted so their hashes can't be recomputed.
+type SearchQuerySalt struct {
+	Day  string `gorm:"primaryKey;size:10"`
+	Salt string `gorm:"size:64;not null"`
+}
+
+func (SearchQuerySalt) TableName() string {
+	return "search_query_salts"
+}
+
+var searchSaltCache struct {
+	sync.Mutex
+	day  string
+	salt []byte
+}
+
+// HashSearcher hashes searcher with today's salt, creating the salt on the
+// first search of the day. Every server shares the salt through the table.
+func HashSearcher(db *gorm.DB, searcher string) (string, error) {
+	salt, err := searchSalt(db, time.Now().UTC().Format("2006-01-02"))
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(searcher))
+	return hex.EncodeToString(mac.Sum(nil)[:16]), nil
+}
+
+func searchSalt(db *gorm.DB, day string) ([]byte, error) {
+	searchSaltCache.Lock()
+	defer searchSaltCache.Unlock()
+
+	if searchSaltCache.day == day {
+		return searchSaltCache.salt, nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&SearchQuerySalt{Day: day, Salt: hex.EncodeToString(random)}).Error; err != nil {
+		return nil, err
+	}
+
+	var stored SearchQuerySalt
+	if err := db.Where("day = ?", day).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("day < ?", day).Delete(&SearchQuerySalt{}).Error; err != nil {
+		return nil, err
+	}
+
+	searchSaltCache.day = day
+	searchSaltCache.salt = []byte(stored.Salt)
+	return searchSaltCache.salt, nil
+}
+
+// ClearLegacySearcherHashes blanks searcher hashes written with the old
+// long-lived key, which linked a user's queries across the whole retention
+// window. Current hashes are half as long.
+func ClearLegacySearcherHashes(db *gorm.DB) (int64, error) {
+	result := db.Model(&SearchQueryLog{}).
+		Where("length(searcher_hash) = 64").
+		UpdateColumn("searcher_hash", "")
+	return result.RowsAffected, result.Error
+}
+
+// NormalizeSearchQuery lowercases the query and collapses whitespace so that
+// equivalent searches aggregate together.
+func NormalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+func DeleteOldSearchQueryLogs(db *gorm.DB, olderThan time.Time) (int64, error) {
+	result := db.Where("created_at < ?", olderThan).Delete(&SearchQueryLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Series groups a host's rooms into an episodic show. Episodes are rooms
+// with SeriesID set, ordered by season then episode number.
+type Series struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	HostID      uint   `gorm:"not null;index" json:"host_id"`
+	Host        User   `gorm:"foreignKey:HostID" json:"host"`
+	Title       string `gorm:"size:150;not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	ArtworkURL  string `json:"artwork_url"`
+	Category    string `json:"category"`
+	TopicID     *uint  `gorm:"index" json:"topic_id"`
+
+	SubscribersCount int `gorm:"default:0" json:"subscribers_count"`
+
+	// EpisodesCount is only filled by queries that select it; see
+	// WithEpisodesCount.
+	EpisodesCount int `gorm:"->;-:migration" json:"episodes_count"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+type SeriesSubscription struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	SeriesID  uint      `gorm:"not null;uniqueIndex:idx_series_subscription" json:"series_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_series_subscription;index" json:"user_id"`
+}
+
+// WithEpisodesCount selects series rows along with their number of public
+// episodes.
+func WithEpisodesCount(query *gorm.DB) *gorm.DB {
+	return query.Select(`series.*, (
+		SELECT COUNT(*) FROM rooms
+		WHERE rooms.series_id = series.id AND rooms.deleted_at IS NULL
+			AND rooms.is_private = false AND rooms.is_hidden = false AND rooms.status = 'published'
+	) AS episodes_count`)
+}
+
+// NextEpisodeNumber returns the number following the highest episode of a
+// season.
+func NextEpisodeNumber(db *gorm.DB, seriesID uint, season int) (int, error) {
+	var highest *int
+	err := db.Model(&Room{}).
+		Where("series_id = ? AND season_number = ?", seriesID, season).
+		Select("MAX(episode_number)").
+		Scan(&highest).Error
+	if err != nil || highest == nil {
+		return 1, err
+	}
+	return *highest + 1, nil
+}
+
+// IsEpisodeSlotTaken reports whether another room already holds the season
+// and episode number in the series.
+func IsEpisodeSlotTaken(db *gorm.DB, seriesID uint, season, episode int, exceptRoomID uint) (bool, error) {
+	var count int64
+	err := db.Model(&Room{}).
+		Where("series_id = ? AND season_number = ? AND episode_number = ? AND id <> ?", seriesID, season, episode, exceptRoomID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Topic struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Slug      string    `gorm:"size:64;uniqueIndex;not null" json:"slug"`
+	Name      string    `gorm:"size:64;not null" json:"name"`
+	Icon      string    `json:"icon"`
+	Position  int       `gorm:"default:0;index" json:"position"`
+	IsActive  bool      `gorm:"default:true;index" json:"is_active"`
+}
+
+func (Topic) TableName() string {
+	return "topics"
+}
+
+type TopicFollow struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_topic_follow_user_topic" json:"user_id"`
+	TopicID   uint      `gorm:"not null;uniqueIndex:idx_topic_follow_user_topic;index" json:"topic_id"`
+	Topic     Topic     `gorm:"foreignKey:TopicID" json:"topic"`
+}
+
+func (TopicFollow) TableName() string {
+	return "topic_follows"
+}
+
+// DefaultTopics is the taxonomy the app shipped with before topics were
+// stored in the database. MigrateTopics seeds it on first run.
+var DefaultTopics = []Topic{
+	{Slug: "technology", Name: "Technology", Icon: "💻", Position: 1},
+	{Slug: "business", Name: "Business", Icon: "💼", Position: 2},
+	{Slug: "gaming", Name: "Gaming", Icon: "🎮", Position: 3},
+	{Slug: "music", Name: "Music", Icon: "🎵", Position: 4},
+	{Slug: "education", Name: "Education", Icon: "📚", Position: 5},
+	{Slug: "health", Name: "Health", Icon: "🩺", Position: 6},
+	{Slug: "entertainment", Name: "Entertainment", Icon: "🎬", Position: 7},
+	{Slug: "sports", Name: "Sports", Icon: "🏅", Position: 8},
+	{Slug: "news", Name: "News", Icon: "📰", Position: 9},
+}
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func Slugify(value string) string {
+	slug := slugInvalidChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(value)), "-")
+	return strings.Trim(slug, "-")
+}
+
+// MigrateTopics seeds the default taxonomy into an empty topics table, adds
+// an inactive topic for every free-text room topic that doesn't match one,
+// and links existing rooms to their topic row. It is safe to run on every
+// start; defaults an admin deleted are not brought back.
+func MigrateTopics(db *gorm.DB) error {
+	var topicCount int64
+	if err := db.Model(&Topic{}).Count(&topicCount).Error; err != nil {
+		return err
+	}
+	if topicCount == 0 {
+		defaults := make([]Topic, len(DefaultTopics))
+		copy(defaults, DefaultTopics)
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaults).Error; err != nil {
+			return err
+		}
+	}
+
+	var legacyTopics []string
+	if err := db.Model(&Room{}).
+		Where("topic_id IS NULL AND topic <> ''").
+		Distinct("topic").
+		Pluck("topic", &legacyTopics).Error; err != nil {
+		return err
+	}
+
+	for _, name := range legacyTopics {
+		slug := Slugify(name)
+		if slug == "" || strings.EqualFold(name, "All") {
+			continue
+		}
+		topic := Topic{Slug: slug, Name: strings.TrimSpace(name), Position: 100, IsActive: false}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&topic).Error; err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(`
+		UPDATE rooms SET topic_id = topics.id, topic = topics.name
+		FROM topics
+		WHERE rooms.topic_id IS NULL
+			AND (LOWER(rooms.topic) = LOWER(topics.name) OR LOWER(rooms.topic) = topics.slug)
+	`).Error
+}
+
+// FindActiveTopic resolves a topic by slug or display name, case-insensitively.
+func FindActiveTopic(db *gorm.DB, value string) (*Topic, error) {
+	value = strings.TrimSpace(value)
+	var topic Topic
+	err := db.Where("is_active = ?", true).
+		Where("slug = ? OR LOWER(name) = LOWER(?)", Slugify(value), value).
+		First(&topic).Error
+	if err != nil {
+		return nil, err
+	}
+	return &topic, nil
+}
+
+// Matches reports whether value names this topic by slug or display name,
+// using the same rules as FindActiveTopic.
+func (t *Topic) Matches(value string) bool {
+	value = strings.TrimSpace(value)
+	return t.Slug == Slugify(value) || strings.EqualFold(t.Name, value)
+}
+
+// GetFollowedTopicNames returns the names of the active topics a user follows,
+// matching the values stored in Room.Topic.
+func GetFollowedTopicNames(db *gorm.DB, userID uint) ([]string, error) {
+	var names []string
+	err := db.Model(&Topic{}).
+		Joins("JOIN topic_follows ON topic_follows.topic_id = topics.id").
+		Where("topic_follows.user_id = ? AND topics.is_active = ?", userID, true).
+		Pluck("topics.name", &names).Error
+	return names, err
+}
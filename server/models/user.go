@@ -25,6 +25,15 @@ type User struct {
 	FollowingCount  int            `gorm:"default:0" json:"following_count"`
 	TotalGiftsValue int64          `gorm:"default:0" json:"total_gifts_value"`
 	Role            string         `gorm:"default:'user'" json:"role"`
+	AllowMentions   bool           `gorm:"default:true" json:"allow_mentions"`
+
+	// PodcastFeedEnabled opts the user's public rooms into an RSS feed.
+	PodcastFeedEnabled  bool `gorm:"default:false" json:"podcast_feed_enabled"`
+	PodcastFeedExplicit bool `gorm:"default:false" json:"podcast_feed_explicit"`
+	// PodcastFeedETag and PodcastFeedModifiedAt remember the last rendered
+	// feed so Last-Modified only moves when the feed content does.
+	PodcastFeedETag       string     `gorm:"column:podcast_feed_etag;size:64" json:"-"`
+	PodcastFeedModifiedAt *time.Time `json:"-"`
 }
 
 func (User) TableName() string {
@@ -1,75 +1,174 @@
 package routes
 
 import (
+	"time"
 	"voxarena_server/controllers"
 	"voxarena_server/middleware"
+	"voxarena_server/models"
 	"voxarena_server/websocket"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	searchLimit  = middleware.RateLimitPolicy{Name: "search", Limit: 60, Window: time.Minute}
+	suggestLimit = middleware.RateLimitPolicy{Name: "suggest", Limit: 300, Window: time.Minute}
+	followLimit  = middleware.RateLimitPolicy{Name: "follow", Limit: 30, Window: time.Minute}
+	likeLimit    = middleware.RateLimitPolicy{Name: "like", Limit: 120, Window: time.Minute}
+	commentLimit = middleware.RateLimitPolicy{Name: "comment", Limit: 20, Window: time.Minute}
+	reportLimit  = middleware.RateLimitPolicy{Name: "report", Limit: 10, Window: time.Hour}
+	uploadLimit  = middleware.RateLimitPolicy{Name: "upload", Limit: 20, Window: time.Hour}
+	giftLimit    = middleware.RateLimitPolicy{Name: "gift", Limit: 60, Window: time.Minute}
+	pingLimit    = middleware.RateLimitPolicy{Name: "playback-ping", Limit: 120, Window: time.Minute}
+)
+
 func SetupRoutes(router *gin.Engine) {
 	router.Use(middleware.CORSMiddleware())
 
+	limiter := middleware.NewMemoryRateLimitStore()
+
 	v1 := router.Group("/api/v1")
 	{
 		v1.GET("/status", controllers.GetStatus)
-		v1.GET("/topics", controllers.GetTopics)
+		v1.GET("/topics", middleware.OptionalAuthMiddleware(), controllers.GetTopics)
+		v1.GET("/topics/trending", controllers.GetTrendingTopics)
+		v1.GET("/gifts", controllers.GetGiftCatalog)
+		v1.GET("/tags/search", controllers.SearchHashtags)
+		v1.GET("/tags/trending", controllers.GetTrendingHashtags)
+		v1.GET("/tags/:name", controllers.GetHashtag)
+		v1.GET("/tags/:name/rooms", middleware.OptionalAuthMiddleware(), controllers.GetHashtagRooms)
+		v1.GET("/tags/:name/posts", middleware.OptionalAuthMiddleware(), controllers.GetHashtagPosts)
 		v1.GET("/discovery", middleware.OptionalAuthMiddleware(), controllers.GetDiscoveryFeed)
+		v1.GET("/users/:id/podcast.rss", controllers.GetPodcastFeed)
+		v1.HEAD("/users/:id/podcast.rss", controllers.GetPodcastFeed)
+		v1.GET("/playlists/shared/:token", middleware.OptionalAuthMiddleware(), controllers.GetSharedPlaylist)
 
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/google", controllers.GoogleAuth)
 		}
 
-		v1.GET("/search", middleware.OptionalAuthMiddleware(), controllers.GlobalSearch)
+		v1.GET("/search", middleware.OptionalAuthMiddleware(), middleware.RateLimit(limiter, searchLimit), controllers.GlobalSearch)
+		v1.GET("/search/suggest", middleware.OptionalAuthMiddleware(), middleware.RateLimit(limiter, suggestLimit), controllers.GetSearchSuggestions)
+		v1.GET("/search/trending", controllers.GetTrendingSearches)
 
 		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(), middleware.IdempotencyMiddleware())
 		{
 			protected.GET("/me", controllers.GetMe)
+
+			protected.GET("/topics/following", controllers.GetFollowedTopics)
+			protected.PUT("/topics/:slug/follow", middleware.RateLimit(limiter, followLimit), controllers.FollowTopic)
+			protected.DELETE("/topics/:slug/follow", middleware.RateLimit(limiter, followLimit), controllers.UnfollowTopic)
+
+			protected.GET("/feed", controllers.GetHomeFeed)
+			protected.POST("/feed/seen", controllers.MarkFeedItemsSeen)
 			protected.GET("/ws", websocket.HandleWebSocket)
 
 			protected.GET("/profile", controllers.GetUserProfile)
 			protected.PUT("/profile", controllers.UpdateUserProfile)
 			protected.POST("/profile/upload-image", controllers.UploadProfileImage)
+			protected.GET("/profile/podcast-feed", controllers.GetPodcastFeedSettings)
+			protected.PUT("/profile/podcast-feed", controllers.UpdatePodcastFeedSettings)
 
 			protected.GET("/users/:id", controllers.GetUserProfileByID)
 			protected.GET("/users/:id/rooms", controllers.GetUserRooms)
 
-			protected.POST("/rooms", controllers.CreateRoom)
+			protected.POST("/rooms", middleware.RateLimit(limiter, uploadLimit), controllers.CreateRoom)
 			protected.GET("/rooms", controllers.GetRooms)
 			protected.GET("/rooms/:id", controllers.GetRoomByID)
 			protected.GET("/my-rooms", controllers.GetMyRooms)
 			protected.PUT("/rooms/:id", controllers.UpdateRoom)
 			protected.PUT("/rooms/:id/privacy", controllers.UpdateRoomPrivacy)
+			protected.PUT("/rooms/:id/status", controllers.UpdateRoomStatus)
 			protected.DELETE("/rooms/:id", controllers.DeleteRoom)
 
 			protected.POST("/rooms/:id/start-listening", controllers.StartListening)
 			protected.POST("/rooms/:id/stop-listening", controllers.StopListening)
 			protected.GET("/rooms/:id/listeners", controllers.GetListenerCount)
 			protected.PUT("/listen-history/:id", controllers.UpdateListenHistory)
+			protected.POST("/listen-history/:id/events", middleware.RateLimit(limiter, pingLimit), controllers.RecordPlaybackEvents)
 			protected.GET("/my-history", controllers.GetUserListenHistory)
+			protected.GET("/continue-listening", controllers.GetContinueListening)
+			protected.GET("/rooms/:id/playback-state", controllers.GetPlaybackState)
+			protected.PUT("/rooms/:id/playback-state", middleware.RateLimit(limiter, pingLimit), controllers.UpdatePlaybackState)
 			protected.DELETE("/listen-history/:id", controllers.DeleteListenHistory)
 
-			protected.POST("/rooms/:id/like", controllers.ToggleLike)
+			protected.POST("/rooms/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.ToggleLike)
+			protected.PUT("/rooms/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.LikeRoom)
+			protected.DELETE("/rooms/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.UnlikeRoom)
 			protected.GET("/rooms/:id/like-status", controllers.CheckIfLiked)
 
-			protected.POST("/rooms/:id/comments", controllers.CreateComment)
+			protected.POST("/rooms/:id/comments", middleware.RateLimit(limiter, commentLimit), controllers.CreateComment)
 			protected.GET("/rooms/:id/comments", controllers.GetComments)
 			protected.GET("/comments/:id/replies", controllers.GetReplies)
 			protected.DELETE("/comments/:id", controllers.DeleteComment)
-			protected.POST("/comments/:id/like", controllers.ToggleCommentLike)
+			protected.POST("/comments/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.ToggleCommentLike)
 
-			protected.POST("/users/:id/follow", controllers.ToggleFollow)
+			protected.POST("/users/:id/follow", middleware.RateLimit(limiter, followLimit), controllers.ToggleFollow)
+			protected.PUT("/users/:id/follow", middleware.RateLimit(limiter, followLimit), controllers.FollowUser)
+			protected.DELETE("/users/:id/follow", middleware.RateLimit(limiter, followLimit), controllers.UnfollowUser)
 			protected.GET("/users/:id/follow-status", controllers.CheckFollowStatus)
 			protected.GET("/users/:id/followers", controllers.GetFollowers)
 			protected.GET("/users/:id/following", controllers.GetFollowing)
 			protected.GET("/following/rooms", controllers.GetFollowingRooms)
-			protected.DELETE("/users/:id/remove-follower", controllers.RemoveFollower)
+			protected.DELETE("/users/:id/remove-follower", middleware.RateLimit(limiter, followLimit), controllers.RemoveFollower)
+
+			protected.GET("/wallet", controllers.GetWallet)
+			protected.GET("/wallet/packs", controllers.GetCoinPacks)
+			protected.GET("/wallet/transactions", controllers.GetWalletTransactions)
+			protected.POST("/wallet/top-up", controllers.TopUpWallet)
+			protected.POST("/rooms/:id/gifts", middleware.RateLimit(limiter, giftLimit), controllers.SendRoomGift)
+			protected.GET("/rooms/:id/gifts", controllers.GetRoomGifts)
+			protected.POST("/community-posts/:id/gifts", middleware.RateLimit(limiter, giftLimit), controllers.SendCommunityPostGift)
+			protected.GET("/community-posts/:id/gifts", controllers.GetCommunityPostGifts)
+
+			protected.POST("/playlists", middleware.RateLimit(limiter, uploadLimit), controllers.CreatePlaylist)
+			protected.GET("/playlists/mine", controllers.GetMyPlaylists)
+			protected.GET("/playlists/following", controllers.GetFollowedPlaylists)
+			protected.GET("/users/:id/playlists", controllers.GetUserPlaylists)
+			protected.GET("/playlists/:id", controllers.GetPlaylist)
+			protected.PUT("/playlists/:id", controllers.UpdatePlaylist)
+			protected.DELETE("/playlists/:id", controllers.DeletePlaylist)
+			protected.POST("/playlists/:id/items", controllers.AddPlaylistItem)
+			protected.DELETE("/playlists/:id/items/:itemId", controllers.RemovePlaylistItem)
+			protected.PUT("/playlists/:id/items/:itemId/position", controllers.MovePlaylistItem)
+			protected.PUT("/playlists/:id/collaborators/:userId", controllers.AddPlaylistCollaborator)
+			protected.DELETE("/playlists/:id/collaborators/:userId", controllers.RemovePlaylistCollaborator)
+			protected.POST("/playlists/:id/share-link", controllers.CreatePlaylistShareLink)
+			protected.DELETE("/playlists/:id/share-link", controllers.RevokePlaylistShareLink)
+			protected.PUT("/playlists/:id/follow", middleware.RateLimit(limiter, followLimit), controllers.FollowPlaylist)
+			protected.DELETE("/playlists/:id/follow", middleware.RateLimit(limiter, followLimit), controllers.UnfollowPlaylist)
+
+			protected.POST("/series", middleware.RateLimit(limiter, uploadLimit), controllers.CreateSeries)
+			protected.GET("/series/subscriptions", controllers.GetSubscribedSeries)
+			protected.GET("/users/:id/series", controllers.GetUserSeries)
+			protected.GET("/series/:id", controllers.GetSeries)
+			protected.PUT("/series/:id", controllers.UpdateSeries)
+			protected.DELETE("/series/:id", controllers.DeleteSeries)
+			protected.PUT("/series/:id/episodes/:roomId", controllers.SetSeriesEpisode)
+			protected.DELETE("/series/:id/episodes/:roomId", controllers.RemoveSeriesEpisode)
+			protected.PUT("/series/:id/subscribe", middleware.RateLimit(limiter, followLimit), controllers.SubscribeSeries)
+			protected.DELETE("/series/:id/subscribe", middleware.RateLimit(limiter, followLimit), controllers.UnsubscribeSeries)
+
+			protected.POST("/imports", middleware.RateLimit(limiter, uploadLimit), controllers.CreateFeedImport)
+			protected.GET("/imports", controllers.GetFeedImports)
+			protected.GET("/imports/:id", controllers.GetFeedImport)
+			protected.POST("/imports/:id/retry", controllers.RetryFeedImport)
+			protected.POST("/imports/:id/items/:itemId/skip", controllers.SkipFeedImportItem)
+
+			protected.GET("/analytics/channel", controllers.GetChannelAnalytics)
+			protected.GET("/analytics/rooms/:id", controllers.GetRoomAnalytics)
+			protected.GET("/analytics/rooms/:id/retention", controllers.GetRoomRetention)
 
 			protected.POST("/queue/smart", controllers.GetSmartQueue)
-			protected.GET("/queue/search", controllers.GetQueueFromSearch)
+			protected.GET("/queue/search", middleware.RateLimit(limiter, searchLimit), controllers.GetQueueFromSearch)
+
+			protected.GET("/saved-searches", controllers.GetSavedSearches)
+			protected.POST("/saved-searches", controllers.CreateSavedSearch)
+			protected.PUT("/saved-searches/:id", controllers.UpdateSavedSearch)
+			protected.DELETE("/saved-searches/:id", controllers.DeleteSavedSearch)
+			protected.GET("/saved-searches/:id/results", middleware.RateLimit(limiter, searchLimit), controllers.GetSavedSearchResults)
 
 			protected.POST("/users/:id/hide", controllers.ToggleHideUser)
 			protected.GET("/users/:id/hide-status", controllers.CheckHiddenStatus)
@@ -80,28 +179,30 @@ func SetupRoutes(router *gin.Engine) {
 			protected.DELETE("/download-history/:id", controllers.DeleteDownloadHistory)
 			protected.DELETE("/download-history", controllers.ClearAllDownloadHistory)
 
-			protected.POST("/community-posts", controllers.CreateCommunityPost)
+			protected.POST("/community-posts", middleware.RateLimit(limiter, uploadLimit), controllers.CreateCommunityPost)
 			protected.GET("/community-posts", controllers.GetCommunityPosts)
 			protected.GET("/community-posts/:id", controllers.GetCommunityPostByID)
 			protected.GET("/users/:id/community-posts", controllers.GetUserCommunityPosts)
 			protected.PUT("/community-posts/:id", controllers.UpdateCommunityPost)
 			protected.DELETE("/community-posts/:id", controllers.DeleteCommunityPost)
 
-			protected.POST("/community-posts/:id/like", controllers.ToggleCommunityPostLike)
+			protected.POST("/community-posts/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.ToggleCommunityPostLike)
+			protected.PUT("/community-posts/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.LikeCommunityPost)
+			protected.DELETE("/community-posts/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.UnlikeCommunityPost)
 			protected.GET("/community-posts/:id/like-status", controllers.CheckCommunityPostLikeStatus)
 
-			protected.POST("/community-posts/:id/comments", controllers.CreateCommunityPostComment)
+			protected.POST("/community-posts/:id/comments", middleware.RateLimit(limiter, commentLimit), controllers.CreateCommunityPostComment)
 			protected.GET("/community-posts/:id/comments", controllers.GetCommunityPostComments)
 			protected.DELETE("/community-comments/:id", controllers.DeleteCommunityPostComment)
 
-			protected.POST("/community-comments/:id/like", controllers.ToggleCommunityCommentLike)
+			protected.POST("/community-comments/:id/like", middleware.RateLimit(limiter, likeLimit), controllers.ToggleCommunityCommentLike)
 			protected.GET("/community-comments/:id/like-status", controllers.CheckCommunityCommentLikeStatus)
 			protected.GET("/community-comments/:id/replies", controllers.GetCommunityCommentReplies)
 
 			protected.POST("/rooms/:id/record-listen", controllers.RecordUniqueListenIfNew)
 
-			protected.POST("/rooms/:id/report", controllers.ReportRoom)
-			protected.GET("/rooms/:id/reports", controllers.GetRoomReports)
+			protected.POST("/rooms/:id/report", middleware.RateLimit(limiter, reportLimit), controllers.ReportRoom)
+			protected.GET("/rooms/:id/reports", middleware.RequirePermission(models.PermissionReportsRead), controllers.GetRoomReports)
 			protected.GET("/rooms/:id/report-status", controllers.CheckIfReported)
 
 			protected.GET("/notifications", controllers.GetNotifications)
@@ -109,6 +210,26 @@ func SetupRoutes(router *gin.Engine) {
 			protected.PUT("/notifications/mark-all-read", controllers.MarkAllNotificationsAsRead)
 			protected.DELETE("/notifications/:id", controllers.DeleteNotification)
 		}
+
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(), middleware.IdempotencyMiddleware())
+		{
+			admin.GET("/roles", middleware.RequirePermission(models.PermissionRolesGrant), controllers.GetRoles)
+			admin.PUT("/users/:id/role", middleware.RequirePermission(models.PermissionRolesGrant), controllers.UpdateUserRole)
+			admin.PUT("/users/:id/ban", middleware.RequirePermission(models.PermissionUsersBan), controllers.UpdateUserBanStatus)
+			admin.GET("/rooms/:id/reports", middleware.RequirePermission(models.PermissionReportsRead), controllers.GetRoomReports)
+			admin.PUT("/rooms/:id/moderation", middleware.RequirePermission(models.PermissionRoomsModerate), controllers.ModerateRoom)
+			admin.GET("/topics", middleware.RequirePermission(models.PermissionTopicsManage), controllers.AdminGetTopics)
+			admin.POST("/topics", middleware.RequirePermission(models.PermissionTopicsManage), controllers.AdminCreateTopic)
+			admin.PUT("/topics/:id", middleware.RequirePermission(models.PermissionTopicsManage), controllers.AdminUpdateTopic)
+			admin.DELETE("/topics/:id", middleware.RequirePermission(models.PermissionTopicsManage), controllers.AdminDeleteTopic)
+			admin.GET("/gift-types", middleware.RequirePermission(models.PermissionGiftsManage), controllers.AdminGetGiftTypes)
+			admin.POST("/gift-types", middleware.RequirePermission(models.PermissionGiftsManage), controllers.AdminCreateGiftType)
+			admin.PUT("/gift-types/:id", middleware.RequirePermission(models.PermissionGiftsManage), controllers.AdminUpdateGiftType)
+			admin.POST("/gifts/:id/refund", middleware.RequirePermission(models.PermissionGiftsManage), controllers.AdminRefundGift)
+			admin.POST("/ledger/:id/refund", middleware.RequirePermission(models.PermissionGiftsManage), controllers.AdminRefundPurchase)
+			admin.POST("/counters/reconcile", middleware.RequirePermission(models.PermissionSystemMaintain), controllers.ReconcileCounters)
+		}
 	}
 
 	router.GET("/", func(c *gin.Context) {
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartAnalyticsScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(1 * time.Hour)
+	analytics := services.NewAnalyticsService(db)
+
+	refresh := func() {
+		if err := analytics.RefreshRecent(); err != nil {
+			log.Printf("Error refreshing analytics rollups: %v", err)
+		}
+	}
+
+	go refresh()
+
+	go func() {
+		for range ticker.C {
+			refresh()
+		}
+	}()
+}
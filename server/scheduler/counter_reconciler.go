@@ -0,0 +1,26 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartCounterReconcileScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(6 * time.Hour)
+	reconciler := services.NewCounterReconciler(db)
+
+	go func() {
+		for range ticker.C {
+			log.Println("Running scheduled counter reconciliation...")
+			discrepancies, err := reconciler.Reconcile()
+			if err != nil {
+				log.Printf("Error during counter reconciliation: %v", err)
+			} else {
+				log.Printf("Counter reconciliation completed, fixed %d counters", len(discrepancies))
+			}
+		}
+	}()
+}
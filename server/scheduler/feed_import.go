@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartFeedImportScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(10 * time.Minute)
+	imports := services.NewFeedImportService(db)
+
+	if err := imports.ResumeInterrupted(); err != nil {
+		log.Printf("Error resuming feed imports: %v", err)
+	}
+
+	pickUp := func() {
+		if err := imports.StartPending(); err != nil {
+			log.Printf("Error starting pending feed imports: %v", err)
+		}
+	}
+
+	go pickUp()
+
+	go func() {
+		for range ticker.C {
+			pickUp()
+		}
+	}()
+}
@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+func StartFeedSeenCleanupScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(24 * time.Hour)
+
+	go func() {
+		for range ticker.C {
+			deleted, err := models.DeleteOldFeedSeenItems(db, time.Now().Add(-models.FeedSeenItemRetention))
+			if err != nil {
+				log.Printf("Error during feed seen item cleanup: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d old feed seen items", deleted)
+			}
+		}
+	}()
+}
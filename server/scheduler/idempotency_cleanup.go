@@ -0,0 +1,25 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/middleware"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+func StartIdempotencyCleanupScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(time.Hour)
+
+	go func() {
+		for range ticker.C {
+			deleted, err := models.DeleteExpiredIdempotencyKeys(db, time.Now().Add(-middleware.IdempotencyKeyRetention))
+			if err != nil {
+				log.Printf("Error during idempotency key cleanup: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}()
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartRecommendationScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(6 * time.Hour)
+	recommendations := services.NewRecommendationService(db)
+
+	rebuild := func() {
+		started := time.Now()
+		count, err := recommendations.RebuildSimilarities()
+		if err != nil {
+			log.Printf("Error rebuilding room similarities: %v", err)
+			return
+		}
+		log.Printf("Rebuilt %d room similarities in %s", count, time.Since(started).Round(time.Millisecond))
+	}
+
+	go rebuild()
+
+	go func() {
+		for range ticker.C {
+			rebuild()
+		}
+	}()
+}
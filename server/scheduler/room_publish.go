@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartRoomPublishScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(1 * time.Minute)
+	publisher := services.NewRoomPublishService(db)
+
+	publish := func() {
+		published, err := publisher.PublishDue()
+		if err != nil {
+			log.Printf("Error publishing scheduled rooms: %v", err)
+		} else if published > 0 {
+			log.Printf("Published %d scheduled rooms", published)
+		}
+	}
+
+	go publish()
+
+	go func() {
+		for range ticker.C {
+			publish()
+		}
+	}()
+}
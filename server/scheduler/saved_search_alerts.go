@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartSavedSearchAlertScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(15 * time.Minute)
+
+	go func() {
+		for range ticker.C {
+			if err := services.RunSavedSearchAlerts(db); err != nil {
+				log.Printf("Error running saved search alerts: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+func StartSearchQueryCleanupScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(24 * time.Hour)
+
+	go func() {
+		for range ticker.C {
+			deleted, err := models.DeleteOldSearchQueryLogs(db, time.Now().Add(-models.SearchQueryLogRetention))
+			if err != nil {
+				log.Printf("Error during search query log cleanup: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d old search query logs", deleted)
+			}
+		}
+	}()
+}
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"voxarena_server/services"
+
+	"gorm.io/gorm"
+)
+
+func StartTrendingScheduler(db *gorm.DB) {
+	ticker := time.NewTicker(15 * time.Minute)
+	trending := services.NewTrendingService(db)
+
+	recompute := func() {
+		if _, err := trending.Recompute(); err != nil {
+			log.Printf("Error recomputing trending scores: %v", err)
+		}
+	}
+
+	go recompute()
+
+	go func() {
+		for range ticker.C {
+			recompute()
+		}
+	}()
+}
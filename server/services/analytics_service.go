@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+// AnalyticsBackfillWindow is how far back the first rollup reaches when the
+// rollup tables are empty.
+const AnalyticsBackfillWindow = 365 * 24 * time.Hour
+
+// AnalyticsService precomputes per-room and per-channel daily and weekly
+// rollups from the raw listen, like, comment, download and follow tables, so
+// creator dashboards read a handful of rows instead of scanning history.
+type AnalyticsService struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsService(db *gorm.DB) *AnalyticsService {
+	return &AnalyticsService{db: db}
+}
+
+// completionExpr normalises completion_rate to 0..1; older clients report
+// percentages.
+const completionExpr = "CASE WHEN lh.completion_rate > 1 THEN lh.completion_rate / 100 ELSE lh.completion_rate END"
+
+// progressReported is true for listens whose client sent a progress update;
+// listens that only started carry no completion signal.
+const progressReported = "(lh.duration > 0 OR lh.last_position > 0 OR lh.is_completed OR lh.is_skipped)"
+
+func bucketExpr(column string) string {
+	return fmt.Sprintf("CAST(date_trunc(@period, %s AT TIME ZONE 'UTC') AS date)", column)
+}
+
+// Refresh recomputes every day and week bucket from since's bucket onwards.
+// Listen progress arrives after the listen starts, so callers should re-run
+// recent buckets rather than only the current one. Existing rows in the
+// window are replaced, so buckets whose source rows were all deleted drop out
+// instead of keeping stale counts.
+func (as *AnalyticsService) Refresh(since time.Time) error {
+	for _, period := range []string{models.AnalyticsPeriodDay, models.AnalyticsPeriodWeek} {
+		start := models.AnalyticsPeriodStart(period, since)
+		err := as.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("period = ? AND period_start >= CAST(? AS date)", period, start).
+				Delete(&models.RoomStat{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("period = ? AND period_start >= CAST(? AS date)", period, start).
+				Delete(&models.ChannelStat{}).Error; err != nil {
+				return err
+			}
+			if err := rollUpRooms(tx, period, start); err != nil {
+				return fmt.Errorf("room %s rollup: %w", period, err)
+			}
+			if err := rollUpChannels(tx, period, start); err != nil {
+				return fmt.Errorf("channel %s rollup: %w", period, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshRecent rolls up the last two weeks, or the whole backfill window if
+// no rollups exist yet.
+func (as *AnalyticsService) RefreshRecent() error {
+	since := time.Now().AddDate(0, 0, -14)
+
+	var count int64
+	if err := as.db.Model(&models.ChannelStat{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		since = time.Now().Add(-AnalyticsBackfillWindow)
+	}
+
+	return as.Refresh(since)
+}
+
+func rollUpRooms(tx *gorm.DB, period string, since time.Time) error {
+	sql := `
+		WITH listens AS (
+			SELECT lh.room_id, ` + bucketExpr("lh.listened_at") + ` AS bucket,
+				COUNT(*) AS listens,
+				COUNT(DISTINCT lh.user_id) AS unique_listeners,
+				COALESCE(SUM(` + completionExpr + `) FILTER (WHERE ` + progressReported + `), 0) AS completion_sum,
+				COUNT(*) FILTER (WHERE ` + progressReported + `) AS completion_count,
+				COUNT(*) FILTER (WHERE lh.is_completed) AS completions,
+				COUNT(*) FILTER (WHERE lh.is_skipped) AS skips
+			FROM listen_history lh
+			WHERE lh.listened_at >= @since
+			GROUP BY 1, 2
+		),
+		new_listeners AS (
+			SELECT ul.room_id, ` + bucketExpr("ul.created_at") + ` AS bucket, COUNT(*) AS n
+			FROM unique_room_listens ul
+			WHERE ul.created_at >= @since AND ul.deleted_at IS NULL
+			GROUP BY 1, 2
+		),
+		likes AS (
+			SELECT rl.room_id, ` + bucketExpr("rl.created_at") + ` AS bucket, COUNT(*) AS n
+			FROM room_likes rl
+			WHERE rl.created_at >= @since
+			GROUP BY 1, 2
+		),
+		comments AS (
+			SELECT cm.room_id, ` + bucketExpr("cm.created_at") + ` AS bucket, COUNT(*) AS n
+			FROM comments cm
+			WHERE cm.created_at >= @since AND cm.deleted_at IS NULL
+			GROUP BY 1, 2
+		),
+		downloads AS (
+			SELECT dh.room_id, ` + bucketExpr("dh.downloaded_at") + ` AS bucket, COUNT(*) AS n
+			FROM download_history dh
+			WHERE dh.downloaded_at >= @since
+			GROUP BY 1, 2
+		),
+		buckets AS (
+			SELECT room_id, bucket FROM listens
+			UNION SELECT room_id, bucket FROM new_listeners
+			UNION SELECT room_id, bucket FROM likes
+			UNION SELECT room_id, bucket FROM comments
+			UNION SELECT room_id, bucket FROM downloads
+		)
+		INSERT INTO room_stats (
+			room_id, period, period_start, host_id,
+			listens, unique_listeners, new_listeners, completion_sum, completion_count,
+			completions, skips, likes, comments, downloads, updated_at
+		)
+		SELECT b.room_id, @period, b.bucket, rooms.host_id,
+			COALESCE(l.listens, 0), COALESCE(l.unique_listeners, 0), COALESCE(nl.n, 0),
+			COALESCE(l.completion_sum, 0), COALESCE(l.completion_count, 0),
+			COALESCE(l.completions, 0), COALESCE(l.skips, 0),
+			COALESCE(lk.n, 0), COALESCE(cm.n, 0), COALESCE(dl.n, 0), NOW()
+		FROM buckets b
+		JOIN rooms ON rooms.id = b.room_id
+		LEFT JOIN listens l ON l.room_id = b.room_id AND l.bucket = b.bucket
+		LEFT JOIN new_listeners nl ON nl.room_id = b.room_id AND nl.bucket = b.bucket
+		LEFT JOIN likes lk ON lk.room_id = b.room_id AND lk.bucket = b.bucket
+		LEFT JOIN comments cm ON cm.room_id = b.room_id AND cm.bucket = b.bucket
+		LEFT JOIN downloads dl ON dl.room_id = b.room_id AND dl.bucket = b.bucket
+		ON CONFLICT (room_id, period, period_start) DO UPDATE SET
+			host_id = EXCLUDED.host_id,
+			listens = EXCLUDED.listens,
+			unique_listeners = EXCLUDED.unique_listeners,
+			new_listeners = EXCLUDED.new_listeners,
+			completion_sum = EXCLUDED.completion_sum,
+			completion_count = EXCLUDED.completion_count,
+			completions = EXCLUDED.completions,
+			skips = EXCLUDED.skips,
+			likes = EXCLUDED.likes,
+			comments = EXCLUDED.comments,
+			downloads = EXCLUDED.downloads,
+			updated_at = EXCLUDED.updated_at
+	`
+	return tx.Exec(sql, map[string]interface{}{"period": period, "since": since}).Error
+}
+
+func rollUpChannels(tx *gorm.DB, period string, since time.Time) error {
+	sql := `
+		WITH listens AS (
+			SELECT rooms.host_id, ` + bucketExpr("lh.listened_at") + ` AS bucket,
+				COUNT(*) AS listens,
+				COUNT(DISTINCT lh.user_id) AS unique_listeners,
+				COALESCE(SUM(` + completionExpr + `) FILTER (WHERE ` + progressReported + `), 0) AS completion_sum,
+				COUNT(*) FILTER (WHERE ` + progressReported + `) AS completion_count,
+				COUNT(*) FILTER (WHERE lh.is_completed) AS completions,
+				COUNT(*) FILTER (WHERE lh.is_skipped) AS skips
+			FROM listen_history lh
+			JOIN rooms ON rooms.id = lh.room_id
+			WHERE lh.listened_at >= @since
+			GROUP BY 1, 2
+		),
+		room_totals AS (
+			SELECT host_id, period_start AS bucket,
+				SUM(new_listeners) AS new_listeners,
+				SUM(likes) AS likes,
+				SUM(comments) AS comments,
+				SUM(downloads) AS downloads
+			FROM room_stats
+			WHERE period = @period AND period_start >= CAST(@since AS date)
+			GROUP BY 1, 2
+		),
+		followers AS (
+			SELECT f.following_id AS host_id, ` + bucketExpr("f.created_at") + ` AS bucket, COUNT(*) AS n
+			FROM follows f
+			WHERE f.created_at >= @since
+			GROUP BY 1, 2
+		),
+		buckets AS (
+			SELECT host_id, bucket FROM listens
+			UNION SELECT host_id, bucket FROM room_totals
+			UNION SELECT host_id, bucket FROM followers
+		)
+		INSERT INTO channel_stats (
+			host_id, period, period_start,
+			listens, unique_listeners, new_listeners, completion_sum, completion_count,
+			completions, skips, likes, comments, downloads, new_followers, updated_at
+		)
+		SELECT b.host_id, @period, b.bucket,
+			COALESCE(l.listens, 0), COALESCE(l.unique_listeners, 0), COALESCE(rt.new_listeners, 0),
+			COALESCE(l.completion_sum, 0), COALESCE(l.completion_count, 0),
+			COALESCE(l.completions, 0), COALESCE(l.skips, 0),
+			COALESCE(rt.likes, 0), COALESCE(rt.comments, 0), COALESCE(rt.downloads, 0),
+			COALESCE(f.n, 0), NOW()
+		FROM buckets b
+		LEFT JOIN listens l ON l.host_id = b.host_id AND l.bucket = b.bucket
+		LEFT JOIN room_totals rt ON rt.host_id = b.host_id AND rt.bucket = b.bucket
+		LEFT JOIN followers f ON f.host_id = b.host_id AND f.bucket = b.bucket
+		ON CONFLICT (host_id, period, period_start) DO UPDATE SET
+			listens = EXCLUDED.listens,
+			unique_listeners = EXCLUDED.unique_listeners,
+			new_listeners = EXCLUDED.new_listeners,
+			completion_sum = EXCLUDED.completion_sum,
+			completion_count = EXCLUDED.completion_count,
+			completions = EXCLUDED.completions,
+			skips = EXCLUDED.skips,
+			likes = EXCLUDED.likes,
+			comments = EXCLUDED.comments,
+			downloads = EXCLUDED.downloads,
+			new_followers = EXCLUDED.new_followers,
+			updated_at = EXCLUDED.updated_at
+	`
+	return tx.Exec(sql, map[string]interface{}{"period": period, "since": since}).Error
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// counterSource describes how to recompute one denormalized counter column
+// from its source table.
+type counterSource struct {
+	Table       string
+	Column      string
+	SourceTable string
+	ForeignKey  string
+	SoftDeleted bool
+}
+
+var counterSources = []counterSource{
+	{Table: "rooms", Column: "likes_count", SourceTable: "room_likes", ForeignKey: "room_id"},
+	{Table: "rooms", Column: "total_listens", SourceTable: "unique_room_listens", ForeignKey: "room_id", SoftDeleted: true},
+	{Table: "rooms", Column: "report_count", SourceTable: "room_reports", ForeignKey: "room_id"},
+	{Table: "users", Column: "followers_count", SourceTable: "follows", ForeignKey: "following_id"},
+	{Table: "users", Column: "following_count", SourceTable: "follows", ForeignKey: "follower_id"},
+	{Table: "comments", Column: "likes_count", SourceTable: "comment_likes", ForeignKey: "comment_id"},
+	{Table: "community_posts", Column: "likes_count", SourceTable: "community_post_likes", ForeignKey: "community_post_id", SoftDeleted: true},
+	{Table: "community_posts", Column: "comments_count", SourceTable: "community_post_comments", ForeignKey: "community_post_id", SoftDeleted: true},
+	{Table: "community_post_comments", Column: "likes_count", SourceTable: "community_comment_likes", ForeignKey: "comment_id", SoftDeleted: true},
+}
+
+type CounterDiscrepancy struct {
+	Table    string `json:"table"`
+	Column   string `json:"column"`
+	ID       uint   `json:"id"`
+	Stored   int64  `json:"stored"`
+	Expected int64  `json:"expected"`
+}
+
+type CounterReconciler struct {
+	db *gorm.DB
+}
+
+func NewCounterReconciler(db *gorm.DB) *CounterReconciler {
+	return &CounterReconciler{db: db}
+}
+
+// Reconcile recomputes every counter from its source rows, fixes the ones
+// that drifted and returns what was changed.
+func (cr *CounterReconciler) Reconcile() ([]CounterDiscrepancy, error) {
+	discrepancies := []CounterDiscrepancy{}
+
+	for _, source := range counterSources {
+		fixed, err := cr.reconcileCounter(source)
+		if err != nil {
+			return discrepancies, fmt.Errorf("failed to reconcile %s.%s: %w", source.Table, source.Column, err)
+		}
+		discrepancies = append(discrepancies, fixed...)
+	}
+
+	return discrepancies, nil
+}
+
+func (cr *CounterReconciler) reconcileCounter(source counterSource) ([]CounterDiscrepancy, error) {
+	sourceFilter := ""
+	if source.SoftDeleted {
+		sourceFilter = "AND s.deleted_at IS NULL"
+	}
+
+	query := fmt.Sprintf(`
+		WITH expected AS (
+			SELECT t.id, COUNT(s.id) AS value
+			FROM %[1]s t
+			LEFT JOIN %[3]s s ON s.%[4]s = t.id %[5]s
+			WHERE t.deleted_at IS NULL
+			GROUP BY t.id
+		),
+		drifted AS (
+			SELECT t.id, t.%[2]s AS stored, e.value AS expected
+			FROM %[1]s t
+			INNER JOIN expected e ON e.id = t.id
+			WHERE t.%[2]s <> e.value
+		)
+		UPDATE %[1]s t
+		SET %[2]s = d.expected
+		FROM drifted d
+		WHERE t.id = d.id
+		RETURNING t.id, d.stored, d.expected
+	`, source.Table, source.Column, source.SourceTable, source.ForeignKey, sourceFilter)
+
+	var rows []struct {
+		ID       uint
+		Stored   int64
+		Expected int64
+	}
+	if err := cr.db.Raw(query).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	discrepancies := make([]CounterDiscrepancy, 0, len(rows))
+	for _, row := range rows {
+		discrepancies = append(discrepancies, CounterDiscrepancy{
+			Table:    source.Table,
+			Column:   source.Column,
+			ID:       row.ID,
+			Stored:   row.Stored,
+			Expected: row.Expected,
+		})
+		log.Printf("⚠️ Counter drift fixed: %s.%s id=%d stored=%d expected=%d",
+			source.Table, source.Column, row.ID, row.Stored, row.Expected)
+	}
+
+	return discrepancies, nil
+}
@@ -0,0 +1,628 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"voxarena_server/models"
+	"voxarena_server/utils"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	MaxImportFeedBytes   = 10 * 1024 * 1024
+	MaxImportAudioBytes  = 50 * 1024 * 1024
+	MaxImportImageBytes  = 5 * 1024 * 1024
+	MaxImportFeedItems   = 1000
+	maxImportAttempts    = 3
+	importRetryBaseDelay = 5 * time.Second
+)
+
+var (
+	ErrInvalidFeed        = errors.New("not a valid RSS feed")
+	errBlockedImportHost  = errors.New("address is not allowed")
+	errUnsupportedFeedURL = errors.New("only http and https URLs are supported")
+	errAlreadyImported    = errors.New("episode already imported")
+)
+
+type importRSS struct {
+	Channel struct {
+		Title string `xml:"title"`
+		// <image> and <itunes:image> share a local name, so both land here.
+		Images []struct {
+			URL  string `xml:"url"`
+			Href string `xml:"href,attr"`
+		} `xml:"image"`
+		Items []importRSSItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type importRSSItem struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Summary     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Duration    string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesImage struct {
+		Href string `xml:"href,attr"`
+	} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+// ParsedFeed is the subset of an RSS feed an import needs.
+type ParsedFeed struct {
+	Title string
+	Items []models.FeedImportItem
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+func plainText(value string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(value, " ")))
+}
+
+var pubDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	time.RFC3339,
+}
+
+func parsePubDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseITunesDuration accepts seconds, MM:SS or HH:MM:SS.
+func parseITunesDuration(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	seconds := 0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + int(n)
+	}
+	return seconds
+}
+
+// ParsePodcastFeed reads an RSS 2.0 podcast feed. Items without an audio
+// enclosure come back already skipped so the import report lists them.
+func ParsePodcastFeed(data []byte) (*ParsedFeed, error) {
+	var feed importRSS
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&feed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+
+	if len(feed.Channel.Items) == 0 && strings.TrimSpace(feed.Channel.Title) == "" {
+		return nil, ErrInvalidFeed
+	}
+	if len(feed.Channel.Items) > MaxImportFeedItems {
+		return nil, fmt.Errorf("feed has more than %d episodes", MaxImportFeedItems)
+	}
+
+	channelImage := ""
+	for _, image := range feed.Channel.Images {
+		if href := strings.TrimSpace(image.Href); href != "" {
+			channelImage = href
+			break
+		}
+		if channelImage == "" {
+			channelImage = strings.TrimSpace(image.URL)
+		}
+	}
+
+	parsed := &ParsedFeed{Title: strings.TrimSpace(feed.Channel.Title)}
+	seen := map[string]bool{}
+
+	for _, entry := range feed.Channel.Items {
+		audioURL := strings.TrimSpace(entry.Enclosure.URL)
+		guid := strings.TrimSpace(entry.GUID)
+		if guid == "" {
+			guid = audioURL
+		}
+
+		description := entry.Description
+		if strings.TrimSpace(description) == "" {
+			description = entry.Summary
+		}
+
+		imageURL := strings.TrimSpace(entry.ITunesImage.Href)
+		if imageURL == "" {
+			imageURL = channelImage
+		}
+
+		item := models.FeedImportItem{
+			GUID:        guid,
+			Title:       plainText(entry.Title),
+			Description: plainText(description),
+			AudioURL:    audioURL,
+			ImageURL:    imageURL,
+			Duration:    parseITunesDuration(entry.Duration),
+			PublishedAt: parsePubDate(entry.PubDate),
+			Status:      models.FeedImportItemPending,
+		}
+
+		switch {
+		case audioURL == "":
+			item.Status = models.FeedImportItemSkipped
+			item.Error = "No audio enclosure"
+			if item.GUID == "" {
+				item.GUID = item.Title
+			}
+		case seen[guid]:
+			item.Status = models.FeedImportItemSkipped
+			item.Error = "Duplicate GUID in feed"
+		}
+		seen[guid] = true
+
+		parsed.Items = append(parsed.Items, item)
+	}
+
+	return parsed, nil
+}
+
+// newImportHTTPClient returns a client that refuses to connect to loopback,
+// private and link-local addresses, since feed and enclosure URLs come from
+// users. The check runs on the resolved address, so DNS can't route around it.
+func newImportHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errBlockedImportHost
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+func download(client *http.Client, rawURL string, maxBytes int64) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, errUnsupportedFeedURL
+	}
+
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("file is larger than %d MB", maxBytes/(1024*1024))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("file is larger than %d MB", maxBytes/(1024*1024))
+	}
+	return data, nil
+}
+
+// FeedImportService runs podcast feed imports. Each import is processed by a
+// single worker, one episode at a time, oldest first.
+type FeedImportService struct {
+	db         *gorm.DB
+	feedClient *http.Client
+	fileClient *http.Client
+}
+
+func NewFeedImportService(db *gorm.DB) *FeedImportService {
+	return &FeedImportService{
+		db:         db,
+		feedClient: newImportHTTPClient(30 * time.Second),
+		fileClient: newImportHTTPClient(5 * time.Minute),
+	}
+}
+
+// FetchFeed downloads a feed document from a URL.
+func (fs *FeedImportService) FetchFeed(feedURL string) ([]byte, error) {
+	return download(fs.feedClient, feedURL, MaxImportFeedBytes)
+}
+
+// CreateImport stores a pending import with its parsed items. Episodes the
+// user already imported before are marked skipped up front.
+func (fs *FeedImportService) CreateImport(userID uint, sourceURL string, topic models.Topic, isPrivate bool, feed *ParsedFeed) (*models.FeedImport, error) {
+	feedImport := models.FeedImport{
+		UserID:    userID,
+		SourceURL: sourceURL,
+		FeedTitle: feed.Title,
+		Topic:     topic.Name,
+		TopicID:   topic.ID,
+		IsPrivate: isPrivate,
+		Status:    models.FeedImportStatusPending,
+	}
+
+	err := fs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&feedImport).Error; err != nil {
+			return err
+		}
+		if len(feed.Items) == 0 {
+			return nil
+		}
+
+		guids := make([]string, 0, len(feed.Items))
+		for _, item := range feed.Items {
+			guids = append(guids, item.GUID)
+		}
+		var existing []string
+		if err := tx.Model(&models.Room{}).
+			Where("host_id = ? AND external_guid IN ?", userID, guids).
+			Pluck("external_guid", &existing).Error; err != nil {
+			return err
+		}
+		imported := make(map[string]bool, len(existing))
+		for _, guid := range existing {
+			imported[guid] = true
+		}
+
+		items := make([]models.FeedImportItem, len(feed.Items))
+		for i, item := range feed.Items {
+			item.ImportID = feedImport.ID
+			if item.Status == models.FeedImportItemPending && imported[item.GUID] {
+				item.Status = models.FeedImportItemSkipped
+				item.Error = "Already imported"
+			}
+			items[i] = item
+		}
+		if err := tx.CreateInBatches(items, 200).Error; err != nil {
+			return err
+		}
+
+		return RefreshFeedImportCounts(tx, feedImport.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fs.db.First(&feedImport, feedImport.ID)
+	return &feedImport, nil
+}
+
+// RefreshFeedImportCounts recomputes an import's progress from its items.
+func RefreshFeedImportCounts(db *gorm.DB, importID uint) error {
+	return db.Exec(`
+		UPDATE feed_imports SET
+			total_items = counts.total,
+			imported_items = counts.imported,
+			skipped_items = counts.skipped,
+			failed_items = counts.failed,
+			updated_at = NOW()
+		FROM (
+			SELECT
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE status = ?) AS imported,
+				COUNT(*) FILTER (WHERE status = ?) AS skipped,
+				COUNT(*) FILTER (WHERE status = ?) AS failed
+			FROM feed_import_items WHERE import_id = ?
+		) AS counts
+		WHERE feed_imports.id = ?
+	`, models.FeedImportItemImported, models.FeedImportItemSkipped, models.FeedImportItemFailed, importID, importID).Error
+}
+
+// Start runs the import in the background.
+func (fs *FeedImportService) Start(importID uint) {
+	go func() {
+		if err := fs.Run(importID); err != nil {
+			log.Printf("Feed import %d failed: %v", importID, err)
+		}
+	}()
+}
+
+// Run claims a pending import and processes its pending items. It returns
+// immediately if another worker already claimed the import.
+func (fs *FeedImportService) Run(importID uint) error {
+	now := time.Now()
+	claim := fs.db.Model(&models.FeedImport{}).
+		Where("id = ? AND status = ?", importID, models.FeedImportStatusPending).
+		Updates(map[string]interface{}{"status": models.FeedImportStatusRunning, "started_at": now, "finished_at": nil, "error": ""})
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	var feedImport models.FeedImport
+	if err := fs.db.First(&feedImport, importID).Error; err != nil {
+		return err
+	}
+
+	var lastUpload time.Time
+
+	for {
+		var item models.FeedImportItem
+		err := fs.db.Where("import_id = ? AND status = ?", importID, models.FeedImportItemPending).
+			Order("published_at ASC NULLS FIRST, id ASC").
+			First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// RetryFailed may have queued items since the lookup above.
+			completed, err := fs.complete(importID)
+			if err != nil {
+				fs.finish(importID, models.FeedImportStatusFailed, err.Error())
+				return err
+			}
+			if completed {
+				return nil
+			}
+			continue
+		}
+		if err != nil {
+			fs.finish(importID, models.FeedImportStatusFailed, err.Error())
+			return err
+		}
+
+		fs.processItem(&feedImport, &item, &lastUpload)
+
+		if err := RefreshFeedImportCounts(fs.db, importID); err != nil {
+			log.Printf("Failed to update progress of feed import %d: %v", importID, err)
+		}
+	}
+}
+
+// complete marks the import completed unless pending items are left. The
+// import row is locked first, as in RetryFailed, so a retry either lands
+// before the check or sees the import already completed and restarts it.
+func (fs *FeedImportService) complete(importID uint) (bool, error) {
+	completed := false
+	err := fs.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.FeedImport
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, importID).Error; err != nil {
+			return err
+		}
+
+		var pending int64
+		if err := tx.Model(&models.FeedImportItem{}).
+			Where("import_id = ? AND status = ?", importID, models.FeedImportItemPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return nil
+		}
+
+		if err := RefreshFeedImportCounts(tx, importID); err != nil {
+			return err
+		}
+		completed = true
+		return tx.Model(&models.FeedImport{}).Where("id = ?", importID).
+			Updates(map[string]interface{}{"status": models.FeedImportStatusCompleted, "error": "", "finished_at": time.Now()}).Error
+	})
+	return completed, err
+}
+
+func (fs *FeedImportService) finish(importID uint, status, message string) {
+	if err := RefreshFeedImportCounts(fs.db, importID); err != nil {
+		log.Printf("Failed to update progress of feed import %d: %v", importID, err)
+	}
+	if err := fs.db.Model(&models.FeedImport{}).Where("id = ?", importID).
+		Updates(map[string]interface{}{"status": status, "error": message, "finished_at": time.Now()}).Error; err != nil {
+		log.Printf("Failed to finish feed import %d: %v", importID, err)
+	}
+}
+
+// processItem imports one episode, retrying with backoff before marking it
+// failed. Failed items can be queued again with RetryFailed.
+func (fs *FeedImportService) processItem(feedImport *models.FeedImport, item *models.FeedImportItem, lastUpload *time.Time) {
+	var existing int64
+	fs.db.Model(&models.Room{}).
+		Where("host_id = ? AND external_guid = ?", feedImport.UserID, item.GUID).
+		Count(&existing)
+	if existing > 0 {
+		fs.db.Model(item).Updates(map[string]interface{}{"status": models.FeedImportItemSkipped, "error": "Already imported"})
+		return
+	}
+
+	for {
+		item.Attempts++
+		roomID, err := fs.importItem(feedImport, item, lastUpload)
+		if errors.Is(err, errAlreadyImported) {
+			fs.db.Model(item).Updates(map[string]interface{}{"status": models.FeedImportItemSkipped, "error": "Already imported"})
+			return
+		}
+		if err == nil {
+			fs.db.Model(item).Updates(map[string]interface{}{
+				"status":   models.FeedImportItemImported,
+				"attempts": item.Attempts,
+				"error":    "",
+				"room_id":  roomID,
+			})
+			return
+		}
+
+		if item.Attempts >= maxImportAttempts {
+			fs.db.Model(item).Updates(map[string]interface{}{
+				"status":   models.FeedImportItemFailed,
+				"attempts": item.Attempts,
+				"error":    err.Error(),
+			})
+			return
+		}
+
+		fs.db.Model(item).Updates(map[string]interface{}{"attempts": item.Attempts, "error": err.Error()})
+		time.Sleep(importRetryBaseDelay * time.Duration(item.Attempts))
+	}
+}
+
+func (fs *FeedImportService) importItem(feedImport *models.FeedImport, item *models.FeedImportItem, lastUpload *time.Time) (uint, error) {
+	audioData, err := download(fs.fileClient, item.AudioURL, MaxImportAudioBytes)
+	if err != nil {
+		return 0, fmt.Errorf("download audio: %w", err)
+	}
+
+	// Cloudinary public IDs are per user per second; space uploads out so
+	// consecutive episodes never collide.
+	if wait := time.Until(lastUpload.Add(time.Second)); wait > 0 {
+		time.Sleep(wait)
+	}
+	audioURL, err := utils.UploadAudioToCloudinary(audioData, fmt.Sprint(feedImport.UserID))
+	*lastUpload = time.Now()
+	if err != nil {
+		return 0, fmt.Errorf("upload audio: %w", err)
+	}
+
+	thumbnailURL := ""
+	if item.ImageURL != "" {
+		if imageData, err := download(fs.fileClient, item.ImageURL, MaxImportImageBytes); err == nil {
+			if uploaded, err := utils.UploadThumbnailToCloudinary(imageData, fmt.Sprint(feedImport.UserID)); err == nil {
+				thumbnailURL = uploaded
+			} else {
+				log.Printf("Feed import %d: artwork upload for item %d failed: %v", feedImport.ID, item.ID, err)
+			}
+		} else {
+			log.Printf("Feed import %d: artwork download for item %d failed: %v", feedImport.ID, item.ID, err)
+		}
+	}
+
+	title := item.Title
+	if title == "" {
+		title = "Untitled episode"
+	}
+	guid := item.GUID
+	topicID := feedImport.TopicID
+
+	// Imported back catalogues don't notify followers; they're not new.
+	room := models.Room{
+		Title:        title,
+		Description:  item.Description,
+		Topic:        feedImport.Topic,
+		TopicID:      &topicID,
+		AudioURL:     audioURL,
+		ThumbnailURL: thumbnailURL,
+		Duration:     item.Duration,
+		HostID:       feedImport.UserID,
+		IsPrivate:    feedImport.IsPrivate,
+		ExternalGUID: &guid,
+	}
+	if item.PublishedAt != nil && item.PublishedAt.Before(time.Now()) {
+		room.CreatedAt = *item.PublishedAt
+		room.PublishedAt = item.PublishedAt
+	}
+
+	if err := fs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&room).Error; err != nil {
+			return err
+		}
+		return models.SyncRoomHashtags(tx, room.ID, room.Description)
+	}); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, errAlreadyImported
+		}
+		return 0, fmt.Errorf("create room: %w", err)
+	}
+
+	return room.ID, nil
+}
+
+// RetryFailed queues an import's failed items again and restarts it.
+func (fs *FeedImportService) RetryFailed(importID uint) (int64, error) {
+	var retried int64
+	err := fs.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.FeedImport
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, importID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.FeedImportItem{}).
+			Where("import_id = ? AND status = ?", importID, models.FeedImportItemFailed).
+			Updates(map[string]interface{}{"status": models.FeedImportItemPending, "attempts": 0, "error": ""})
+		if result.Error != nil {
+			return result.Error
+		}
+		retried = result.RowsAffected
+		if retried == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.FeedImport{}).
+			Where("id = ? AND status <> ?", importID, models.FeedImportStatusRunning).
+			Update("status", models.FeedImportStatusPending).Error; err != nil {
+			return err
+		}
+		return RefreshFeedImportCounts(tx, importID)
+	})
+	if err == nil && retried > 0 {
+		fs.Start(importID)
+	}
+	return retried, err
+}
+
+// ResumeInterrupted requeues imports left running by a previous process.
+// Only one server instance should call it, and only at startup.
+func (fs *FeedImportService) ResumeInterrupted() error {
+	return fs.db.Model(&models.FeedImport{}).
+		Where("status = ?", models.FeedImportStatusRunning).
+		Update("status", models.FeedImportStatusPending).Error
+}
+
+// StartPending starts every pending import. Imports already claimed by a
+// worker are left alone by Run.
+func (fs *FeedImportService) StartPending() error {
+	var pending []uint
+	if err := fs.db.Model(&models.FeedImport{}).
+		Where("status = ?", models.FeedImportStatusPending).
+		Pluck("id", &pending).Error; err != nil {
+		return err
+	}
+	for _, id := range pending {
+		fs.Start(id)
+	}
+	return nil
+}
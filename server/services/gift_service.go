@@ -0,0 +1,370 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("insufficient coin balance")
+	ErrAlreadyRefunded     = errors.New("already refunded")
+	ErrNotRefundable       = errors.New("transaction cannot be refunded")
+
+	errReferenceExists = errors.New("ledger reference already posted")
+)
+
+// GiftService moves coins through the double-entry ledger. Every balance
+// change is a LedgerTransaction whose entries sum to zero; wallet balances
+// are updated in the same database transaction as the entries.
+type GiftService struct {
+	db       *gorm.DB
+	payments PaymentProvider
+}
+
+func NewGiftService(db *gorm.DB, payments PaymentProvider) *GiftService {
+	return &GiftService{db: db, payments: payments}
+}
+
+type ledgerMove struct {
+	walletID uint
+	amount   int64
+	// requireFunds rejects a debit that would take the wallet below zero.
+	requireFunds bool
+}
+
+// GetWallet returns the user's wallet of the given kind, creating it empty.
+func (gs *GiftService) GetWallet(tx *gorm.DB, userID uint, kind string) (*models.Wallet, error) {
+	wallet := models.Wallet{UserID: &userID, Kind: kind}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&wallet).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ? AND kind = ?", userID, kind).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// issuanceWallet returns the system issuance wallet. It is normally seeded at
+// startup; idx_wallet_system_kind keeps concurrent creation to one row.
+func (gs *GiftService) issuanceWallet(tx *gorm.DB) (*models.Wallet, error) {
+	wallet := models.Wallet{Kind: models.WalletKindIssuance}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&wallet).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id IS NULL AND kind = ?", models.WalletKindIssuance).First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (gs *GiftService) post(tx *gorm.DB, txn *models.LedgerTransaction, moves ...ledgerMove) error {
+	var sum int64
+	for _, move := range moves {
+		sum += move.amount
+	}
+	if sum != 0 {
+		return fmt.Errorf("unbalanced ledger transaction %s: entries sum to %d", txn.Reference, sum)
+	}
+
+	// Concurrent retries of the same operation race on the unique
+	// reference; the loser must not post its entries.
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "reference"}},
+		DoNothing: true,
+	}).Create(txn)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errReferenceExists
+	}
+
+	for _, move := range moves {
+		update := tx.Model(&models.Wallet{}).Where("id = ?", move.walletID)
+		if move.requireFunds && move.amount < 0 {
+			update = update.Where("balance >= ?", -move.amount)
+		}
+		result := update.Update("balance", gorm.Expr("balance + ?", move.amount))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientBalance
+		}
+
+		entry := models.LedgerEntry{TransactionID: txn.ID, WalletID: move.walletID, Amount: move.amount}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TopUp charges the user for a coin pack and credits the coins. Retrying
+// with the same payment token returns the original purchase.
+func (gs *GiftService) TopUp(userID uint, pack CoinPack, paymentToken string) (*models.LedgerTransaction, error) {
+	if gs.payments == nil {
+		return nil, ErrPaymentsNotConfigured
+	}
+
+	charge, err := gs.payments.Charge(userID, pack, paymentToken)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := "purchase:" + charge.ProviderRef
+	var existing models.LedgerTransaction
+	if err := gs.db.Where("reference = ?", reference).First(&existing).Error; err == nil {
+		return &existing, nil
+	}
+
+	txn := models.LedgerTransaction{
+		Kind:        models.LedgerKindPurchase,
+		Reference:   reference,
+		InitiatorID: userID,
+		Memo:        fmt.Sprintf("%s (%d %s cents)", pack.ID, charge.AmountCents, charge.Currency),
+	}
+
+	err = gs.db.Transaction(func(tx *gorm.DB) error {
+		issuance, err := gs.issuanceWallet(tx)
+		if err != nil {
+			return err
+		}
+		coins, err := gs.GetWallet(tx, userID, models.WalletKindCoins)
+		if err != nil {
+			return err
+		}
+		return gs.post(tx, &txn,
+			ledgerMove{walletID: issuance.ID, amount: -pack.Coins},
+			ledgerMove{walletID: coins.ID, amount: pack.Coins},
+		)
+	})
+	if err != nil {
+		// A concurrent retry with the same token may have credited the
+		// charge already; only refund when no purchase was recorded.
+		var posted models.LedgerTransaction
+		if lookupErr := gs.db.Where("reference = ?", reference).First(&posted).Error; lookupErr == nil {
+			return &posted, nil
+		} else if !errors.Is(lookupErr, gorm.ErrRecordNotFound) {
+			log.Printf("⚠️ Not refunding charge %s: purchase lookup failed: %v", charge.ProviderRef, lookupErr)
+			return nil, err
+		}
+
+		// The charge went through but the coins were not credited; give
+		// the money back rather than leave the user paying for nothing.
+		if refundErr := gs.payments.Refund(charge.ProviderRef); refundErr != nil {
+			log.Printf("⚠️ Failed to refund charge %s after ledger error: %v", charge.ProviderRef, refundErr)
+		}
+		return nil, err
+	}
+
+	return &txn, nil
+}
+
+// SendGiftInput describes a gift from sender to the owner of a room or post.
+type SendGiftInput struct {
+	Sender      models.User
+	RecipientID uint
+	GiftType    models.GiftType
+	Quantity    int
+	TargetType  string
+	TargetID    uint
+	Message     string
+}
+
+// SendGift debits the sender's coins and credits the recipient's earnings in
+// one transaction. The debit only succeeds if the balance covers it, so
+// concurrent gifts cannot overdraw a wallet.
+func (gs *GiftService) SendGift(input SendGiftInput) (*models.Gift, error) {
+	amount := input.GiftType.CoinPrice * int64(input.Quantity)
+
+	gift := models.Gift{
+		SenderID:    input.Sender.ID,
+		RecipientID: input.RecipientID,
+		GiftTypeID:  input.GiftType.ID,
+		TargetType:  input.TargetType,
+		TargetID:    input.TargetID,
+		Quantity:    input.Quantity,
+		CoinAmount:  amount,
+		Message:     input.Message,
+	}
+
+	err := gs.db.Transaction(func(tx *gorm.DB) error {
+		senderCoins, err := gs.GetWallet(tx, input.Sender.ID, models.WalletKindCoins)
+		if err != nil {
+			return err
+		}
+		recipientEarnings, err := gs.GetWallet(tx, input.RecipientID, models.WalletKindEarnings)
+		if err != nil {
+			return err
+		}
+
+		txn := models.LedgerTransaction{
+			Kind:        models.LedgerKindGift,
+			Reference:   "gift:" + randomReference(),
+			InitiatorID: input.Sender.ID,
+			Memo:        fmt.Sprintf("%dx %s on %s %d", input.Quantity, input.GiftType.Slug, input.TargetType, input.TargetID),
+		}
+		if err := gs.post(tx, &txn,
+			ledgerMove{walletID: senderCoins.ID, amount: -amount, requireFunds: true},
+			ledgerMove{walletID: recipientEarnings.ID, amount: amount},
+		); err != nil {
+			return err
+		}
+
+		gift.TransactionID = txn.ID
+		if err := tx.Create(&gift).Error; err != nil {
+			return err
+		}
+
+		return RecomputeTotalGiftsValue(tx, input.RecipientID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gift.Sender = input.Sender
+	gift.GiftType = input.GiftType
+
+	go func() {
+		if err := NewNotificationService(gs.db).NotifyGift(&gift); err != nil {
+			log.Printf("⚠️ Failed to send gift notification: %v", err)
+		}
+	}()
+
+	return &gift, nil
+}
+
+// RefundGift reverses a gift: the coins go back to the sender and the value
+// leaves the recipient's earnings, even if that takes them below zero.
+func (gs *GiftService) RefundGift(giftID, adminID uint, reason string) (*models.Gift, error) {
+	var gift models.Gift
+	err := gs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&gift, giftID).Error; err != nil {
+			return err
+		}
+		if gift.RefundedAt != nil {
+			return ErrAlreadyRefunded
+		}
+
+		senderCoins, err := gs.GetWallet(tx, gift.SenderID, models.WalletKindCoins)
+		if err != nil {
+			return err
+		}
+		recipientEarnings, err := gs.GetWallet(tx, gift.RecipientID, models.WalletKindEarnings)
+		if err != nil {
+			return err
+		}
+
+		txn := models.LedgerTransaction{
+			Kind:        models.LedgerKindGiftRefund,
+			Reference:   fmt.Sprintf("gift_refund:%d", gift.ID),
+			InitiatorID: adminID,
+			ReversesID:  &gift.TransactionID,
+			Memo:        reason,
+		}
+		if err := gs.post(tx, &txn,
+			ledgerMove{walletID: recipientEarnings.ID, amount: -gift.CoinAmount},
+			ledgerMove{walletID: senderCoins.ID, amount: gift.CoinAmount},
+		); err != nil {
+			if errors.Is(err, errReferenceExists) {
+				return ErrAlreadyRefunded
+			}
+			return err
+		}
+
+		now := time.Now()
+		gift.RefundedAt = &now
+		if err := tx.Model(&gift).Update("refunded_at", now).Error; err != nil {
+			return err
+		}
+
+		return RecomputeTotalGiftsValue(tx, gift.RecipientID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gift, nil
+}
+
+// RefundPurchase reverses a coin purchase and refunds the payment. It fails
+// with ErrInsufficientBalance if the user already spent the coins.
+func (gs *GiftService) RefundPurchase(transactionID, adminID uint, reason string) (*models.LedgerTransaction, error) {
+	if gs.payments == nil {
+		return nil, ErrPaymentsNotConfigured
+	}
+
+	var refund models.LedgerTransaction
+	err := gs.db.Transaction(func(tx *gorm.DB) error {
+		var purchase models.LedgerTransaction
+		if err := tx.Preload("Entries").First(&purchase, transactionID).Error; err != nil {
+			return err
+		}
+		if purchase.Kind != models.LedgerKindPurchase {
+			return ErrNotRefundable
+		}
+
+		var count int64
+		tx.Model(&models.LedgerTransaction{}).Where("reverses_id = ?", purchase.ID).Count(&count)
+		if count > 0 {
+			return ErrAlreadyRefunded
+		}
+
+		moves := make([]ledgerMove, 0, len(purchase.Entries))
+		for _, entry := range purchase.Entries {
+			moves = append(moves, ledgerMove{walletID: entry.WalletID, amount: -entry.Amount, requireFunds: true})
+		}
+
+		refund = models.LedgerTransaction{
+			Kind:        models.LedgerKindPurchaseRefund,
+			Reference:   fmt.Sprintf("purchase_refund:%d", purchase.ID),
+			InitiatorID: adminID,
+			ReversesID:  &purchase.ID,
+			Memo:        reason,
+		}
+		if err := gs.post(tx, &refund, moves...); err != nil {
+			if errors.Is(err, errReferenceExists) {
+				return ErrAlreadyRefunded
+			}
+			return err
+		}
+
+		// Refund the payment last so a provider error rolls back the ledger.
+		providerRef := purchase.Reference[len("purchase:"):]
+		return gs.payments.Refund(providerRef)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// RecomputeTotalGiftsValue sets User.TotalGiftsValue to the ledger sum of the
+// user's earnings wallet.
+func RecomputeTotalGiftsValue(tx *gorm.DB, userID uint) error {
+	return tx.Exec(`
+		UPDATE users SET total_gifts_value = COALESCE((
+			SELECT SUM(ledger_entries.amount)
+			FROM ledger_entries
+			JOIN wallets ON wallets.id = ledger_entries.wallet_id
+			WHERE wallets.user_id = ? AND wallets.kind = ?
+		), 0)
+		WHERE id = ?
+	`, userID, models.WalletKindEarnings, userID).Error
+}
+
+func randomReference() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -25,11 +25,6 @@ func (ns *NotificationService) NotifyNewRoom(room *models.Room) error {
 		return fmt.Errorf("failed to get followers: %w", err)
 	}
 
-	if len(follows) == 0 {
-		log.Printf("No followers to notify for room %d", room.ID)
-		return nil
-	}
-
 	var hiddenUsers []models.HiddenUser
 	if err := ns.db.Where("user_id = ?", room.HostID).
 		Find(&hiddenUsers).Error; err != nil {
@@ -48,11 +43,24 @@ func (ns *NotificationService) NotifyNewRoom(room *models.Room) error {
 
 	notifications := make([]models.Notification, 0, len(follows))
 	followerIDs := make([]uint, 0, len(follows))
+	notified := map[uint]struct{}{}
+
+	// Series subscribers get the episode notification instead of the
+	// generic one below.
+	episodeNotifications, err := ns.episodeNotifications(room, host, hiddenSet, notified)
+	if err != nil {
+		return err
+	}
+	notifications = append(notifications, episodeNotifications...)
 
 	for _, follow := range follows {
 		if _, isHidden := hiddenSet[follow.FollowerID]; isHidden {
 			continue
 		}
+		if _, done := notified[follow.FollowerID]; done {
+			continue
+		}
+		notified[follow.FollowerID] = struct{}{}
 
 		notification := models.Notification{
 			UserID:        follow.FollowerID,
@@ -70,8 +78,49 @@ func (ns *NotificationService) NotifyNewRoom(room *models.Room) error {
 		followerIDs = append(followerIDs, follow.FollowerID)
 	}
 
+	// Topic followers hear about public rooms too, unless they already got
+	// the notification above as followers of the host.
+	if room.TopicID != nil && !room.IsPrivate && !room.IsHidden {
+		var topic models.Topic
+		if err := ns.db.First(&topic, *room.TopicID).Error; err != nil {
+			return fmt.Errorf("failed to load topic: %w", err)
+		}
+
+		var topicFollowerIDs []uint
+		if topic.IsActive {
+			if err := ns.db.Model(&models.TopicFollow{}).
+				Where("topic_id = ? AND user_id <> ?", topic.ID, room.HostID).
+				Pluck("user_id", &topicFollowerIDs).Error; err != nil {
+				return fmt.Errorf("failed to get topic followers: %w", err)
+			}
+		}
+
+		for _, userID := range topicFollowerIDs {
+			if _, isHidden := hiddenSet[userID]; isHidden {
+				continue
+			}
+			if _, done := notified[userID]; done {
+				continue
+			}
+			notified[userID] = struct{}{}
+
+			notifications = append(notifications, models.Notification{
+				UserID:        userID,
+				ActorID:       room.HostID,
+				Type:          models.NotificationTypeNewRoom,
+				Title:         fmt.Sprintf("New in %s: %s", topic.Name, host.FullName),
+				Message:       room.Title,
+				ReferenceID:   &room.ID,
+				ReferenceType: "room",
+				ImageURL:      room.ThumbnailURL,
+				ActionURL:     fmt.Sprintf("/rooms/%d", room.ID),
+				IsRead:        false,
+			})
+		}
+	}
+
 	if len(notifications) == 0 {
-		log.Printf("No eligible followers to notify for room %d (all hidden)", room.ID)
+		log.Printf("No eligible followers to notify for room %d", room.ID)
 		return nil
 	}
 
@@ -112,6 +161,95 @@ func (ns *NotificationService) NotifyNewRoom(room *models.Room) error {
 	return nil
 }
 
+// episodeNotifications builds new-episode notifications for subscribers of
+// the room's series, skipping users the host hid and users in notified.
+func (ns *NotificationService) episodeNotifications(room *models.Room, host models.User, hiddenSet, notified map[uint]struct{}) ([]models.Notification, error) {
+	if room.SeriesID == nil || room.IsPrivate || room.IsHidden {
+		return nil, nil
+	}
+
+	var series models.Series
+	if err := ns.db.First(&series, *room.SeriesID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load series: %w", err)
+	}
+
+	var subscriberIDs []uint
+	if err := ns.db.Model(&models.SeriesSubscription{}).
+		Where("series_id = ? AND user_id <> ?", series.ID, room.HostID).
+		Pluck("user_id", &subscriberIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get series subscribers: %w", err)
+	}
+
+	title := fmt.Sprintf("New episode of %s", series.Title)
+	if room.SeasonNumber != nil && room.EpisodeNumber != nil {
+		title = fmt.Sprintf("New episode of %s: S%d E%d", series.Title, *room.SeasonNumber, *room.EpisodeNumber)
+	}
+	imageURL := room.ThumbnailURL
+	if imageURL == "" {
+		imageURL = series.ArtworkURL
+	}
+
+	notifications := make([]models.Notification, 0, len(subscriberIDs))
+	for _, userID := range subscriberIDs {
+		if _, isHidden := hiddenSet[userID]; isHidden {
+			continue
+		}
+		if _, done := notified[userID]; done {
+			continue
+		}
+		notified[userID] = struct{}{}
+
+		notifications = append(notifications, models.Notification{
+			UserID:        userID,
+			ActorID:       host.ID,
+			Type:          models.NotificationTypeNewEpisode,
+			Title:         title,
+			Message:       room.Title,
+			ExtraData:     fmt.Sprintf(`{"series_id":%d}`, series.ID),
+			ReferenceID:   &room.ID,
+			ReferenceType: "room",
+			ImageURL:      imageURL,
+			ActionURL:     fmt.Sprintf("/rooms/%d", room.ID),
+			IsRead:        false,
+		})
+	}
+	return notifications, nil
+}
+
+// NotifyNewEpisode tells series subscribers about an existing room that was
+// just added to the series. New rooms go through NotifyNewRoom instead.
+func (ns *NotificationService) NotifyNewEpisode(room *models.Room) error {
+	var host models.User
+	if err := ns.db.First(&host, room.HostID).Error; err != nil {
+		return fmt.Errorf("failed to load host: %w", err)
+	}
+
+	var hiddenUserIDs []uint
+	if err := ns.db.Model(&models.HiddenUser{}).
+		Where("user_id = ?", room.HostID).
+		Pluck("hidden_user_id", &hiddenUserIDs).Error; err != nil {
+		return fmt.Errorf("failed to get hidden users: %w", err)
+	}
+	hiddenSet := make(map[uint]struct{}, len(hiddenUserIDs))
+	for _, id := range hiddenUserIDs {
+		hiddenSet[id] = struct{}{}
+	}
+
+	notifications, err := ns.episodeNotifications(room, host, hiddenSet, map[uint]struct{}{})
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+
+	if err := ns.db.Create(&notifications).Error; err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		ns.sendRealtimeNotification(notification, host)
+	}
+	return nil
+}
+
 func (ns *NotificationService) NotifyNewComment(room *models.Room, comment *models.Comment, commentAuthor models.User) error {
 	if comment.UserID == room.HostID {
 		return nil
@@ -154,7 +292,7 @@ func (ns *NotificationService) NotifyCommentReply(parentComment *models.Comment,
 		UserID:        parentComment.UserID,
 		ActorID:       reply.UserID,
 		Type:          models.NotificationTypeComment,
-		Title:         fmt.Sprintf("@%s mentioned you in a comment", replier.Username),
+		Title:         fmt.Sprintf("@%s replied to your comment", replier.Username),
 		Message:       reply.Content,
 		ExtraData:     parentComment.Content,
 		ReferenceID:   &room.ID,
@@ -265,7 +403,7 @@ func (ns *NotificationService) NotifyCommunityPostCommentReply(parentComment *mo
 		UserID:        parentComment.UserID,
 		ActorID:       reply.UserID,
 		Type:          models.NotificationTypeCommunityComment,
-		Title:         fmt.Sprintf("@%s mentioned you in a comment", replier.Username),
+		Title:         fmt.Sprintf("@%s replied to your comment", replier.Username),
 		Message:       reply.Content,
 		ExtraData:     parentComment.Content,
 		ReferenceID:   &post.ID,
@@ -386,3 +524,162 @@ func (ns *NotificationService) NotifyNewCommunityPost(post *models.CommunityPost
 
 	return nil
 }
+
+// NotifySavedSearchMatches tells the owner of a saved search that new rooms
+// match it. topRoom is the best match and provides the actor and artwork.
+func (ns *NotificationService) NotifySavedSearchMatches(search *models.SavedSearch, matchCount int, topRoom *models.Room) error {
+	var host models.User
+	if err := ns.db.First(&host, topRoom.HostID).Error; err != nil {
+		return fmt.Errorf("failed to load host: %w", err)
+	}
+
+	message := topRoom.Title
+	if matchCount > 1 {
+		message = fmt.Sprintf("%s and %d more", topRoom.Title, matchCount-1)
+	}
+
+	notification := models.Notification{
+		UserID:        search.UserID,
+		ActorID:       topRoom.HostID,
+		Type:          models.NotificationTypeSavedSearch,
+		Title:         fmt.Sprintf("New results for \"%s\"", search.Name),
+		Message:       message,
+		ReferenceID:   &search.ID,
+		ReferenceType: "saved_search",
+		ImageURL:      topRoom.ThumbnailURL,
+		ActionURL:     fmt.Sprintf("/saved-searches/%d", search.ID),
+		IsRead:        false,
+	}
+
+	if err := ns.db.Create(&notification).Error; err != nil {
+		return fmt.Errorf("failed to create saved search notification: %w", err)
+	}
+
+	ns.sendRealtimeNotification(notification, host)
+	return nil
+}
+
+// MentionTarget describes where a set of mentions was written, for building
+// the notification text and link.
+type MentionTarget struct {
+	Where         string // e.g. "a comment", "a post"
+	Message       string
+	ReferenceID   uint
+	ReferenceType string
+	ImageURL      string
+	ActionURL     string
+}
+
+// NotifyMentions sends one mention notification per mentioned user. Users who
+// hid the author, turned mentions off, or appear in skipUserIDs (because they
+// already get a reply notification for the same content) are skipped.
+func (ns *NotificationService) NotifyMentions(author models.User, mentions []models.Mention, target MentionTarget, skipUserIDs ...uint) error {
+	skip := map[uint]bool{author.ID: true}
+	for _, id := range skipUserIDs {
+		skip[id] = true
+	}
+
+	userIDs := make([]uint, 0, len(mentions))
+	for _, mention := range mentions {
+		if !skip[mention.MentionedUserID] {
+			skip[mention.MentionedUserID] = true
+			userIDs = append(userIDs, mention.MentionedUserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var recipientIDs []uint
+	if err := ns.db.Model(&models.User{}).
+		Where("id IN ? AND allow_mentions = ? AND is_active = ?", userIDs, true, true).
+		Where("id NOT IN (SELECT user_id FROM hidden_users WHERE hidden_user_id = ?)", author.ID).
+		Pluck("id", &recipientIDs).Error; err != nil {
+		return fmt.Errorf("failed to filter mentioned users: %w", err)
+	}
+
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]models.Notification, 0, len(recipientIDs))
+	for _, userID := range recipientIDs {
+		notifications = append(notifications, models.Notification{
+			UserID:        userID,
+			ActorID:       author.ID,
+			Type:          models.NotificationTypeMention,
+			Title:         fmt.Sprintf("@%s mentioned you in %s", author.Username, target.Where),
+			Message:       target.Message,
+			ReferenceID:   &target.ReferenceID,
+			ReferenceType: target.ReferenceType,
+			ImageURL:      target.ImageURL,
+			ActionURL:     target.ActionURL,
+			IsRead:        false,
+		})
+	}
+
+	if err := ns.db.Create(&notifications).Error; err != nil {
+		return fmt.Errorf("failed to create mention notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		ns.sendRealtimeNotification(notification, author)
+	}
+
+	log.Printf("✓ Sent %d mention notifications from user %d", len(notifications), author.ID)
+	return nil
+}
+
+// NotifyGift tells the recipient of a gift who sent it and where.
+func (ns *NotificationService) NotifyGift(gift *models.Gift) error {
+	var title, imageURL, actionURL, referenceType string
+	referenceID := gift.TargetID
+
+	giftName := gift.GiftType.Name
+	if gift.Quantity > 1 {
+		giftName = fmt.Sprintf("%d× %s", gift.Quantity, gift.GiftType.Name)
+	}
+
+	switch gift.TargetType {
+	case models.GiftTargetRoom:
+		var room models.Room
+		if err := ns.db.First(&room, gift.TargetID).Error; err != nil {
+			return fmt.Errorf("failed to load room: %w", err)
+		}
+		title = fmt.Sprintf("@%s sent you %s on \"%s\"", gift.Sender.Username, giftName, room.Title)
+		imageURL = room.ThumbnailURL
+		actionURL = fmt.Sprintf("/rooms/%d", room.ID)
+		referenceType = "room"
+	case models.GiftTargetCommunityPost:
+		title = fmt.Sprintf("@%s sent you %s on your post", gift.Sender.Username, giftName)
+		actionURL = fmt.Sprintf("/profile/%d?postId=%d", gift.RecipientID, gift.TargetID)
+		referenceType = "post"
+	default:
+		return fmt.Errorf("unknown gift target %q", gift.TargetType)
+	}
+
+	if gift.GiftType.IconURL != "" {
+		imageURL = gift.GiftType.IconURL
+	}
+
+	notification := models.Notification{
+		UserID:        gift.RecipientID,
+		ActorID:       gift.SenderID,
+		Type:          models.NotificationTypeGift,
+		Title:         title,
+		Message:       gift.Message,
+		ExtraData:     fmt.Sprintf(`{"gift_id":%d,"coin_amount":%d}`, gift.ID, gift.CoinAmount),
+		ReferenceID:   &referenceID,
+		ReferenceType: referenceType,
+		ImageURL:      imageURL,
+		ActionURL:     actionURL,
+		IsRead:        false,
+	}
+
+	if err := ns.db.Create(&notification).Error; err != nil {
+		return fmt.Errorf("failed to create gift notification: %w", err)
+	}
+
+	ns.sendRealtimeNotification(notification, gift.Sender)
+	return nil
+}
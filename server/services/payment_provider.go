@@ -0,0 +1,123 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	ErrPaymentDeclined       = errors.New("payment declined")
+	ErrPaymentsNotConfigured = errors.New("payments are not configured")
+)
+
+// CoinPack is a purchasable bundle of coins.
+type CoinPack struct {
+	ID         string `json:"id"`
+	Coins      int64  `json:"coins"`
+	PriceCents int64  `json:"price_cents"`
+	Currency   string `json:"currency"`
+}
+
+var CoinPacks = []CoinPack{
+	{ID: "coins_100", Coins: 100, PriceCents: 99, Currency: "USD"},
+	{ID: "coins_550", Coins: 550, PriceCents: 499, Currency: "USD"},
+	{ID: "coins_1200", Coins: 1200, PriceCents: 999, Currency: "USD"},
+	{ID: "coins_6500", Coins: 6500, PriceCents: 4999, Currency: "USD"},
+}
+
+func FindCoinPack(id string) (CoinPack, bool) {
+	for _, pack := range CoinPacks {
+		if pack.ID == id {
+			return pack, true
+		}
+	}
+	return CoinPack{}, false
+}
+
+// PaymentCharge is a settled charge. ProviderRef identifies it at the
+// provider and doubles as the ledger reference of the purchase.
+type PaymentCharge struct {
+	ProviderRef string
+	AmountCents int64
+	Currency    string
+}
+
+// PaymentProvider charges users for coin packs. Charging the same payment
+// token twice must return the same charge rather than billing again.
+type PaymentProvider interface {
+	Charge(userID uint, pack CoinPack, paymentToken string) (*PaymentCharge, error)
+	Refund(providerRef string) error
+}
+
+// Payments is the provider used for top-ups. It is nil, and top-ups are
+// disabled, unless InitPayments configured one.
+var Payments PaymentProvider
+
+// InitPayments selects the payment provider from PAYMENT_PROVIDER. Only the
+// fake provider exists so far; it is meant for development and tests.
+func InitPayments() error {
+	switch provider := os.Getenv("PAYMENT_PROVIDER"); provider {
+	case "":
+		return ErrPaymentsNotConfigured
+	case "fake":
+		Payments = NewFakePaymentProvider()
+		return nil
+	default:
+		return fmt.Errorf("unknown payment provider %q", provider)
+	}
+}
+
+// FakePaymentDeclineToken makes FakePaymentProvider decline the charge.
+const FakePaymentDeclineToken = "tok_decline"
+
+// FakePaymentProvider accepts every token except FakePaymentDeclineToken and
+// keeps charges in memory.
+type FakePaymentProvider struct {
+	mu       sync.Mutex
+	charges  map[string]PaymentCharge
+	refunded map[string]bool
+}
+
+func NewFakePaymentProvider() *FakePaymentProvider {
+	return &FakePaymentProvider{
+		charges:  map[string]PaymentCharge{},
+		refunded: map[string]bool{},
+	}
+}
+
+func (p *FakePaymentProvider) Charge(userID uint, pack CoinPack, paymentToken string) (*PaymentCharge, error) {
+	if paymentToken == "" || paymentToken == FakePaymentDeclineToken {
+		return nil, ErrPaymentDeclined
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, paymentToken)))
+	ref := "fake_ch_" + hex.EncodeToString(sum[:12])
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if charge, ok := p.charges[ref]; ok {
+		return &charge, nil
+	}
+	charge := PaymentCharge{ProviderRef: ref, AmountCents: pack.PriceCents, Currency: pack.Currency}
+	p.charges[ref] = charge
+	return &charge, nil
+}
+
+func (p *FakePaymentProvider) Refund(providerRef string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.charges[providerRef]; !ok {
+		return fmt.Errorf("unknown charge %s", providerRef)
+	}
+	if p.refunded[providerRef] {
+		return fmt.Errorf("charge %s already refunded", providerRef)
+	}
+	p.refunded[providerRef] = true
+	return nil
+}
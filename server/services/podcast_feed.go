@@ -0,0 +1,257 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"voxarena_server/models"
+
+	"github.com/google/uuid"
+)
+
+// MaxPodcastFeedItems caps how many of a host's newest rooms a feed lists.
+const MaxPodcastFeedItems = 300
+
+// podcastGUIDNamespace is the Podcasting 2.0 namespace for podcast:guid,
+// a UUIDv5 of the feed URL without its scheme.
+var podcastGUIDNamespace = uuid.MustParse("ead4c236-bf58-58c6-a2c6-a6b28d128cb6")
+
+// topicITunesCategories maps VoxArena topics to Apple Podcasts categories.
+var topicITunesCategories = map[string]string{
+	"Technology":    "Technology",
+	"Business":      "Business",
+	"Gaming":        "Leisure",
+	"Music":         "Music",
+	"Education":     "Education",
+	"Health":        "Health & Fitness",
+	"Entertainment": "TV & Film",
+	"Sports":        "Sports",
+	"News":          "News",
+}
+
+const defaultITunesCategory = "Society & Culture"
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ITunesNS  string     `xml:"xmlns:itunes,attr"`
+	PodcastNS string     `xml:"xmlns:podcast,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string          `xml:"title"`
+	Link           string          `xml:"link"`
+	AtomLink       rssAtomLink     `xml:"atom:link"`
+	Description    string          `xml:"description"`
+	Language       string          `xml:"language"`
+	LastBuildDate  string          `xml:"lastBuildDate,omitempty"`
+	Generator      string          `xml:"generator"`
+	Image          *rssImage       `xml:"image,omitempty"`
+	ITunesAuthor   string          `xml:"itunes:author"`
+	ITunesSummary  string          `xml:"itunes:summary"`
+	ITunesType     string          `xml:"itunes:type"`
+	ITunesExplicit string          `xml:"itunes:explicit"`
+	ITunesImage    *rssITunesImage `xml:"itunes:image,omitempty"`
+	ITunesOwner    rssITunesOwner  `xml:"itunes:owner"`
+	ITunesCategory rssITunesCat    `xml:"itunes:category"`
+	PodcastGUID    string          `xml:"podcast:guid"`
+	PodcastMedium  string          `xml:"podcast:medium"`
+	Items          []rssItem       `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type rssITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssITunesOwner struct {
+	Name string `xml:"itunes:name"`
+}
+
+type rssITunesCat struct {
+	Text string `xml:"text,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title          string          `xml:"title"`
+	Link           string          `xml:"link"`
+	Description    string          `xml:"description"`
+	GUID           rssGUID         `xml:"guid"`
+	PubDate        string          `xml:"pubDate"`
+	Enclosure      rssEnclosure    `xml:"enclosure"`
+	ITunesTitle    string          `xml:"itunes:title"`
+	ITunesDuration string          `xml:"itunes:duration,omitempty"`
+	ITunesImage    *rssITunesImage `xml:"itunes:image,omitempty"`
+	ITunesExplicit string          `xml:"itunes:explicit"`
+	ITunesSeason   int             `xml:"itunes:season,omitempty"`
+	ITunesEpisode  int             `xml:"itunes:episode,omitempty"`
+	ITunesType     string          `xml:"itunes:episodeType"`
+}
+
+// PodcastFeed describes one host's feed. Rooms must already be filtered to
+// what may be published and ordered newest first.
+type PodcastFeed struct {
+	Host     models.User
+	Rooms    []models.Room
+	FeedURL  string
+	SiteURL  string
+	Explicit bool
+	Category string
+}
+
+// PodcastRoomGUID is the stable item GUID for a room. It must never change,
+// or podcast apps will treat every episode as new; imported rooms keep the
+// GUID they had in their original feed.
+func PodcastRoomGUID(room models.Room) string {
+	if room.ExternalGUID != nil && *room.ExternalGUID != "" {
+		return *room.ExternalGUID
+	}
+	return fmt.Sprintf("voxarena-room-%d", room.ID)
+}
+
+// PodcastFeedGUID derives the podcast:guid for a feed URL.
+func PodcastFeedGUID(feedURL string) string {
+	trimmed := feedURL
+	if i := strings.Index(trimmed, "://"); i >= 0 {
+		trimmed = trimmed[i+3:]
+	}
+	return uuid.NewSHA1(podcastGUIDNamespace, []byte(strings.TrimRight(trimmed, "/"))).String()
+}
+
+// ITunesCategoryForTopic maps a topic name to an Apple Podcasts category.
+func ITunesCategoryForTopic(topic string) string {
+	if category, ok := topicITunesCategories[topic]; ok {
+		return category
+	}
+	return defaultITunesCategory
+}
+
+// AudioMIMEType guesses an enclosure type from the audio URL's extension.
+func AudioMIMEType(audioURL string) string {
+	ext := strings.ToLower(path.Ext(strings.SplitN(audioURL, "?", 2)[0]))
+	switch ext {
+	case ".m4a", ".mp4", ".aac":
+		return "audio/x-m4a"
+	case ".wav":
+		return "audio/wav"
+	case ".ogg", ".oga":
+		return "audio/ogg"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+// Render builds the RSS 2.0 document with iTunes and Podcasting 2.0 tags.
+func (f *PodcastFeed) Render() ([]byte, error) {
+	explicit := "false"
+	if f.Explicit {
+		explicit = "true"
+	}
+
+	title := f.Host.FullName
+	if title == "" {
+		title = f.Host.Username
+	}
+	description := f.Host.Bio
+	if description == "" {
+		description = fmt.Sprintf("Audio from %s on VoxArena", title)
+	}
+
+	channel := rssChannel{
+		Title:          title,
+		Link:           f.SiteURL,
+		AtomLink:       rssAtomLink{Href: f.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		Description:    description,
+		Language:       "en",
+		Generator:      "VoxArena",
+		ITunesAuthor:   title,
+		ITunesSummary:  description,
+		ITunesType:     "episodic",
+		ITunesExplicit: explicit,
+		ITunesOwner:    rssITunesOwner{Name: title},
+		ITunesCategory: rssITunesCat{Text: f.Category},
+		PodcastGUID:    PodcastFeedGUID(f.FeedURL),
+		PodcastMedium:  "podcast",
+		Items:          make([]rssItem, 0, len(f.Rooms)),
+	}
+
+	if f.Host.ProfilePic != "" {
+		channel.Image = &rssImage{URL: f.Host.ProfilePic, Title: title, Link: f.SiteURL}
+		channel.ITunesImage = &rssITunesImage{Href: f.Host.ProfilePic}
+	}
+
+	var lastBuild time.Time
+	for _, room := range f.Rooms {
+		if room.CreatedAt.After(lastBuild) {
+			lastBuild = room.CreatedAt
+		}
+
+		item := rssItem{
+			Title:          room.Title,
+			Link:           fmt.Sprintf("%s/rooms/%d", f.SiteURL, room.ID),
+			Description:    room.Description,
+			GUID:           rssGUID{IsPermaLink: "false", Value: PodcastRoomGUID(room)},
+			PubDate:        room.CreatedAt.UTC().Format(time.RFC1123Z),
+			Enclosure:      rssEnclosure{URL: room.AudioURL, Length: "0", Type: AudioMIMEType(room.AudioURL)},
+			ITunesTitle:    room.Title,
+			ITunesExplicit: explicit,
+			ITunesType:     "full",
+		}
+		if room.Duration > 0 {
+			item.ITunesDuration = strconv.Itoa(room.Duration)
+		}
+		if room.ThumbnailURL != "" {
+			item.ITunesImage = &rssITunesImage{Href: room.ThumbnailURL}
+		}
+		if room.SeasonNumber != nil {
+			item.ITunesSeason = *room.SeasonNumber
+		}
+		if room.EpisodeNumber != nil {
+			item.ITunesEpisode = *room.EpisodeNumber
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+	if !lastBuild.IsZero() {
+		channel.LastBuildDate = lastBuild.UTC().Format(time.RFC1123Z)
+	}
+
+	body, err := xml.MarshalIndent(rssFeed{
+		Version:   "2.0",
+		ITunesNS:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		PodcastNS: "https://podcastindex.org/namespace/1.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		Channel:   channel,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
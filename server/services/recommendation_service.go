@@ -0,0 +1,280 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Only the most recent interactions per user feed the similarity build,
+	// which keeps the pairwise join bounded for heavy listeners.
+	maxInteractionsPerUser = 200
+	interactionWindowDays  = 180
+	maxNeighboursPerRoom   = 50
+	minCoUsers             = 2
+
+	// MaxQueueRoomsPerHost limits how many rooms by the same host the smart
+	// queue may contain.
+	MaxQueueRoomsPerHost = 2
+
+	personalWeight    = 0.7
+	freshnessWeight   = 0.2
+	popularityWeight  = 0.1
+	freshnessHalfLife = 14 * 24 * time.Hour
+)
+
+type RecommendationService struct {
+	db *gorm.DB
+}
+
+func NewRecommendationService(db *gorm.DB) *RecommendationService {
+	return &RecommendationService{db: db}
+}
+
+// RebuildSimilarities recomputes room_similarities as the cosine similarity of
+// rooms over user interaction weights. A listen counts by its completion rate
+// (skips barely count), a like counts 1 and a download 0.8; a user's signals
+// for one room are summed and capped at 2.
+func (rs *RecommendationService) RebuildSimilarities() (int64, error) {
+	var inserted int64
+
+	err := rs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM room_similarities").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			WITH signals AS (
+				SELECT user_id, room_id,
+					CASE
+						WHEN is_skipped THEN 0.1
+						ELSE 0.2 + 0.8 * LEAST(GREATEST(
+							CASE WHEN completion_rate > 1 THEN completion_rate / 100 ELSE completion_rate END,
+						0), 1)
+					END AS weight,
+					listened_at AS at
+				FROM listen_history
+				WHERE deleted_at IS NULL AND listened_at > NOW() - make_interval(days => @window_days)
+
+				UNION ALL
+
+				SELECT user_id, room_id, 1.0, created_at
+				FROM room_likes
+
+				UNION ALL
+
+				SELECT user_id, room_id, 0.8, downloaded_at
+				FROM download_history
+				WHERE deleted_at IS NULL
+			),
+			per_room AS (
+				SELECT user_id, room_id, LEAST(SUM(weight), 2) AS weight, MAX(at) AS last_at
+				FROM signals
+				GROUP BY user_id, room_id
+			),
+			interactions AS (
+				SELECT per_room.user_id, per_room.room_id, per_room.weight
+				FROM (
+					SELECT *, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY last_at DESC) AS recency
+					FROM per_room
+				) per_room
+				INNER JOIN rooms r ON r.id = per_room.room_id AND r.deleted_at IS NULL
+				WHERE per_room.recency <= @max_per_user
+			),
+			norms AS (
+				SELECT room_id, SQRT(SUM(weight * weight)) AS norm
+				FROM interactions
+				GROUP BY room_id
+			),
+			pairs AS (
+				SELECT a.room_id, b.room_id AS similar_room_id,
+					SUM(a.weight * b.weight) AS dot,
+					COUNT(*) AS co_users
+				FROM interactions a
+				INNER JOIN interactions b ON a.user_id = b.user_id AND a.room_id <> b.room_id
+				GROUP BY a.room_id, b.room_id
+				HAVING COUNT(*) >= @min_co_users
+			),
+			scored AS (
+				SELECT pairs.room_id, pairs.similar_room_id, pairs.co_users,
+					pairs.dot / (na.norm * nb.norm) AS score
+				FROM pairs
+				INNER JOIN norms na ON na.room_id = pairs.room_id
+				INNER JOIN norms nb ON nb.room_id = pairs.similar_room_id
+			),
+			ranked AS (
+				SELECT *, ROW_NUMBER() OVER (PARTITION BY room_id ORDER BY score DESC, co_users DESC) AS position
+				FROM scored
+			)
+			INSERT INTO room_similarities (room_id, similar_room_id, score, co_users, updated_at)
+			SELECT room_id, similar_room_id, score, co_users, NOW()
+			FROM ranked
+			WHERE position <= @max_neighbours
+		`, map[string]interface{}{
+			"window_days":    interactionWindowDays,
+			"max_per_user":   maxInteractionsPerUser,
+			"min_co_users":   minCoUsers,
+			"max_neighbours": maxNeighboursPerRoom,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		inserted = result.RowsAffected
+		return nil
+	})
+
+	return inserted, err
+}
+
+type roomCandidate struct {
+	models.Room
+	Affinity float64
+}
+
+// RecommendRooms returns up to limit public rooms for userID, scored by
+// similarity to the rooms they recently engaged with (seedRoomID counts
+// extra), blended with freshness and popularity. excludeRoomIDs and rooms by
+// excludeHostIDs are skipped, and hostCounts is used to keep at most
+// MaxQueueRoomsPerHost rooms per host; it is updated with the picks.
+func (rs *RecommendationService) RecommendRooms(
+	userID uint,
+	seedRoomID uint,
+	excludeRoomIDs []uint,
+	excludeHostIDs []uint,
+	hostCounts map[uint]int,
+	limit int,
+) ([]models.Room, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	query := rs.db.Raw(`
+		WITH seeds AS (
+			SELECT room_id, MAX(weight) AS weight FROM (
+				SELECT room_id, 1.0 AS weight
+				FROM (
+					SELECT room_id FROM listen_history
+					WHERE user_id = @user AND deleted_at IS NULL
+					ORDER BY listened_at DESC
+					LIMIT 50
+				) recent
+				UNION ALL
+				SELECT room_id, 1.0 FROM room_likes WHERE user_id = @user
+				UNION ALL
+				SELECT CAST(@seed AS bigint), 1.5
+			) s
+			GROUP BY room_id
+		)
+		SELECT rooms.*, SUM(room_similarities.score * seeds.weight) AS affinity
+		FROM room_similarities
+		INNER JOIN seeds ON seeds.room_id = room_similarities.room_id
+		INNER JOIN rooms ON rooms.id = room_similarities.similar_room_id
+		WHERE rooms.deleted_at IS NULL
+			AND rooms.is_private = false
+			AND rooms.is_hidden = false
+			AND rooms.status = 'published'
+			AND rooms.id NOT IN @exclude_rooms
+			AND rooms.host_id NOT IN @exclude_hosts
+		GROUP BY rooms.id
+		ORDER BY affinity DESC
+		LIMIT @candidates
+	`, map[string]interface{}{
+		"user":          userID,
+		"seed":          seedRoomID,
+		"exclude_rooms": nonEmptyIDs(excludeRoomIDs),
+		"exclude_hosts": nonEmptyIDs(excludeHostIDs),
+		"candidates":    limit * 5,
+	})
+
+	var candidates []roomCandidate
+	if err := query.Scan(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to load recommendation candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	maxAffinity := candidates[0].Affinity
+	now := time.Now()
+
+	type scoredRoom struct {
+		room  models.Room
+		score float64
+	}
+	scored := make([]scoredRoom, 0, len(candidates))
+	for _, candidate := range candidates {
+		personal := 0.0
+		if maxAffinity > 0 {
+			personal = candidate.Affinity / maxAffinity
+		}
+		age := now.Sub(candidate.CreatedAt)
+		freshness := math.Pow(0.5, age.Hours()/freshnessHalfLife.Hours())
+		popularity := math.Min(1, math.Log1p(float64(candidate.TotalListens+2*candidate.LikesCount))/10)
+
+		scored = append(scored, scoredRoom{
+			room:  candidate.Room,
+			score: personalWeight*personal + freshnessWeight*freshness + popularityWeight*popularity,
+		})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	rooms := make([]models.Room, 0, limit)
+	for _, candidate := range scored {
+		if len(rooms) == limit {
+			break
+		}
+		if hostCounts[candidate.room.HostID] >= MaxQueueRoomsPerHost {
+			continue
+		}
+		hostCounts[candidate.room.HostID]++
+		rooms = append(rooms, candidate.room)
+	}
+
+	if len(rooms) > 0 {
+		if err := rs.loadHosts(rooms); err != nil {
+			log.Printf("Failed to load hosts for recommendations: %v", err)
+		}
+	}
+
+	return rooms, nil
+}
+
+func (rs *RecommendationService) loadHosts(rooms []models.Room) error {
+	hostIDs := make([]uint, 0, len(rooms))
+	for _, room := range rooms {
+		hostIDs = append(hostIDs, room.HostID)
+	}
+
+	var hosts []models.User
+	if err := rs.db.Where("id IN ?", hostIDs).Find(&hosts).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[uint]models.User, len(hosts))
+	for _, host := range hosts {
+		byID[host.ID] = host
+	}
+	for i := range rooms {
+		rooms[i].Host = byID[rooms[i].HostID]
+	}
+	return nil
+}
+
+// nonEmptyIDs keeps "NOT IN" valid when there is nothing to exclude.
+func nonEmptyIDs(ids []uint) []uint {
+	if len(ids) == 0 {
+		return []uint{0}
+	}
+	return ids
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxPlaybackSpeed bounds how far playback can plausibly advance between two
+// pings. A larger jump without a seek event is treated as a gap, not as heard.
+const maxPlaybackSpeed = 3
+
+// PlaybackEvent is one client ping. Position is where playback is after the
+// event; for seeks From is where it was before. Positions are capped at a day
+// so rooms without a known duration still get bounded curves.
+type PlaybackEvent struct {
+	Type     string    `json:"type" binding:"required,oneof=position seek"`
+	Position int       `json:"position" binding:"min=0,max=86400"`
+	From     int       `json:"from" binding:"min=0,max=86400"`
+	At       time.Time `json:"at" binding:"required"`
+}
+
+// RetentionService folds playback pings into per-room coverage and seek
+// hotspots as they arrive, so retention curves never scan raw events.
+type RetentionService struct {
+	db *gorm.DB
+}
+
+func NewRetentionService(db *gorm.DB) *RetentionService {
+	return &RetentionService{db: db}
+}
+
+// RecordEvents applies a batch of pings for one listen and returns how many
+// were accepted. Events at or before the session's last ping are ignored, so
+// retried batches are harmless. Positions are clamped to duration when known.
+func (rs *RetentionService) RecordEvents(history models.ListenHistory, duration int, events []PlaybackEvent) (int, error) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	clamp := func(position int) int {
+		if duration > 0 && position > duration {
+			return duration
+		}
+		return position
+	}
+
+	accepted := 0
+	err := rs.db.Transaction(func(tx *gorm.DB) error {
+		var session models.PlaybackSession
+		hasPrev := true
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&session, "history_id = ?", history.ID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			hasPrev = false
+			session = models.PlaybackSession{HistoryID: history.ID, RoomID: history.RoomID, UserID: history.UserID}
+		}
+
+		covered := map[int]bool{}
+		hotspots := map[int]*models.RetentionHotspot{}
+		hotspot := func(bucket int) *models.RetentionHotspot {
+			if hotspots[bucket] == nil {
+				hotspots[bucket] = &models.RetentionHotspot{RoomID: history.RoomID, Bucket: bucket}
+			}
+			return hotspots[bucket]
+		}
+
+		for _, event := range events {
+			if hasPrev && !event.At.After(session.LastEventAt) {
+				continue
+			}
+
+			position := clamp(event.Position)
+			end := position
+			if event.Type == models.PlaybackEventSeek {
+				end = clamp(event.From)
+			}
+
+			if hasPrev {
+				elapsed := event.At.Sub(session.LastEventAt).Seconds()
+				advanced := end - session.LastPosition
+				if advanced >= 0 && float64(advanced) <= elapsed*maxPlaybackSpeed+models.RetentionBucketSeconds {
+					for b := models.RetentionBucket(session.LastPosition); b <= models.RetentionBucket(end); b++ {
+						covered[b] = true
+					}
+				}
+			}
+			covered[models.RetentionBucket(end)] = true
+
+			if event.Type == models.PlaybackEventSeek {
+				from, to := models.RetentionBucket(end), models.RetentionBucket(position)
+				switch {
+				case to < from:
+					for b := to; b <= from; b++ {
+						hotspot(b).Replays++
+					}
+				case to > from:
+					for b := from; b <= to; b++ {
+						hotspot(b).Skips++
+					}
+				}
+			}
+
+			session.LastPosition = position
+			session.LastEventAt = event.At
+			hasPrev = true
+			accepted++
+		}
+
+		if accepted == 0 {
+			return nil
+		}
+
+		if len(covered) > 0 {
+			rows := make([]models.RetentionCoverage, 0, len(covered))
+			for bucket := range covered {
+				rows = append(rows, models.RetentionCoverage{HistoryID: history.ID, Bucket: bucket, RoomID: history.RoomID})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(hotspots) > 0 {
+			rows := make([]models.RetentionHotspot, 0, len(hotspots))
+			for _, h := range hotspots {
+				rows = append(rows, *h)
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "room_id"}, {Name: "bucket"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"replays": gorm.Expr("retention_hotspots.replays + excluded.replays"),
+					"skips":   gorm.Expr("retention_hotspots.skips + excluded.skips"),
+				}),
+			}).CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Save(&session).Error
+	})
+
+	return accepted, err
+}
+
+// RetentionPoint is one bucket of a room's retention curve.
+type RetentionPoint struct {
+	Bucket    int     `json:"bucket"`
+	Start     int     `json:"start_seconds"`
+	Listeners int64   `json:"listeners"`
+	Retention float64 `json:"retention"`
+	Replays   int64   `json:"replays"`
+	Skips     int64   `json:"skips"`
+}
+
+// RetentionCurve returns the number of listens that reported playback and a
+// zero-filled curve with the percentage of them present at each bucket.
+func (rs *RetentionService) RetentionCurve(roomID uint, duration int) (int64, []RetentionPoint, error) {
+	var sessions int64
+	if err := rs.db.Model(&models.RetentionCoverage{}).
+		Where("room_id = ?", roomID).
+		Distinct("history_id").
+		Count(&sessions).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var counts []struct {
+		Bucket    int
+		Listeners int64
+	}
+	if err := rs.db.Model(&models.RetentionCoverage{}).
+		Select("bucket, COUNT(*) AS listeners").
+		Where("room_id = ?", roomID).
+		Group("bucket").
+		Scan(&counts).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var hotspots []models.RetentionHotspot
+	if err := rs.db.Where("room_id = ?", roomID).Find(&hotspots).Error; err != nil {
+		return 0, nil, err
+	}
+
+	last := -1
+	if duration > 0 {
+		last = models.RetentionBucket(duration)
+	}
+	for _, c := range counts {
+		if c.Bucket > last {
+			last = c.Bucket
+		}
+	}
+	for _, h := range hotspots {
+		if h.Bucket > last {
+			last = h.Bucket
+		}
+	}
+
+	curve := make([]RetentionPoint, last+1)
+	for i := range curve {
+		curve[i] = RetentionPoint{Bucket: i, Start: i * models.RetentionBucketSeconds}
+	}
+	for _, c := range counts {
+		curve[c.Bucket].Listeners = c.Listeners
+		if sessions > 0 {
+			curve[c.Bucket].Retention = float64(c.Listeners) * 100 / float64(sessions)
+		}
+	}
+	for _, h := range hotspots {
+		curve[h.Bucket].Replays = h.Replays
+		curve[h.Bucket].Skips = h.Skips
+	}
+
+	return sessions, curve, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+type RoomPublishService struct {
+	db *gorm.DB
+}
+
+func NewRoomPublishService(db *gorm.DB) *RoomPublishService {
+	return &RoomPublishService{db: db}
+}
+
+// Publish makes a draft or scheduled room public and notifies followers.
+// The room's created_at moves to the scheduled time so it sorts as new in
+// every feed, and published_at records when it actually went public. It returns false when the room was already published, which
+// keeps the scheduler and a manual publish from notifying twice.
+func (ps *RoomPublishService) Publish(roomID uint) (bool, error) {
+	result := ps.db.Model(&models.Room{}).
+		Where("id = ? AND status <> ?", roomID, models.RoomStatusPublished).
+		Updates(map[string]interface{}{
+			"status":       models.RoomStatusPublished,
+			"created_at":   gorm.Expr("LEAST(COALESCE(publish_at, NOW()), NOW())"),
+			"publish_at":   nil,
+			"published_at": gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	var room models.Room
+	if err := ps.db.Preload("Host").First(&room, roomID).Error; err != nil {
+		return true, fmt.Errorf("failed to load published room: %w", err)
+	}
+
+	go func() {
+		if err := NewNotificationService(ps.db).NotifyNewRoom(&room); err != nil {
+			log.Printf("⚠️ Failed to send notifications for room %d: %v", room.ID, err)
+		}
+	}()
+
+	return true, nil
+}
+
+// PublishDue publishes every scheduled room whose publish time has passed.
+func (ps *RoomPublishService) PublishDue() (int, error) {
+	var due []uint
+	if err := ps.db.Model(&models.Room{}).
+		Where("status = ? AND publish_at <= ?", models.RoomStatusScheduled, time.Now()).
+		Order("publish_at ASC").
+		Pluck("id", &due).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, id := range due {
+		ok, err := ps.Publish(id)
+		if err != nil {
+			log.Printf("Error publishing room %d: %v", id, err)
+			continue
+		}
+		if ok {
+			published++
+		}
+	}
+	return published, nil
+}
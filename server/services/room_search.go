@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+type RoomSearchResult struct {
+	ID                 uint   `json:"id"`
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	Topic              string `json:"topic"`
+	AudioURL           string `json:"audio_url"`
+	ThumbnailURL       string `json:"thumbnail_url"`
+	Duration           int    `json:"duration"`
+	HostID             uint   `json:"host_id"`
+	HostName           string `json:"host_name"`
+	HostAvatar         string `json:"host_avatar"`
+	HostFollowersCount int    `json:"host_followers_count"`
+	IsLive             bool   `json:"is_live"`
+	IsPrivate          bool   `json:"is_private"`
+	ListenerCount      int    `json:"listener_count"`
+	TotalListens       int    `json:"total_listens"`
+	LikesCount         int    `json:"likes_count"`
+	CreatedAt          string `json:"created_at"`
+
+	Score              float64 `json:"score"`
+	TitleHighlight     string  `json:"title_highlight"`
+	DescriptionSnippet string  `json:"description_snippet"`
+}
+
+// BuildPrefixTSQuery turns free text into a to_tsquery expression where
+// every word is matched as a prefix, e.g. "jazz pod" -> "jazz:* & pod:*".
+func BuildPrefixTSQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 8 {
+		words = words[:8]
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, word+":*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+// SearchHeadlineOptions and SearchSnippetOptions configure ts_headline for
+// highlighted titles and content snippets.
+const SearchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+const SearchSnippetOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2"
+
+// HighlightSource HTML-escapes a text column before it goes through
+// ts_headline, so the <mark> tags are the only markup in highlights and
+// snippets.
+func HighlightSource(column string) string {
+	return fmt.Sprintf(
+		"replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&quot;')",
+		column,
+	)
+}
+
+type roomSearchRow struct {
+	models.Room
+	Score              float64
+	TitleHighlight     string
+	DescriptionSnippet string
+}
+
+// SearchRooms ranks public, published rooms matching query under filters.
+func SearchRooms(
+	db *gorm.DB,
+	query string,
+	currentUserID uint,
+	filters RoomSearchFilters,
+	limit int,
+) ([]RoomSearchResult, error) {
+
+	filterSQL, params := filters.Conditions(currentUserID)
+	if filterSQL != "" {
+		filterSQL = "AND " + filterSQL
+	}
+	orderBy := filters.OrderBy("score")
+
+	// Relevance is blended with a log-scaled popularity signal so a popular
+	// room can outrank a slightly better textual match, but not a much better one.
+	sql := `
+		WITH params AS (
+			SELECT to_tsquery('english', @tsquery) AS tsq
+		),
+		matches AS (
+			SELECT rooms.*,
+				ts_rank_cd(rooms.search_vector, params.tsq, 32) AS text_rank,
+				GREATEST(similarity(rooms.title, @query), 0.5 * similarity(rooms.topic, @query)) AS title_similarity
+			FROM rooms, params
+			WHERE rooms.deleted_at IS NULL
+				AND rooms.is_private = false
+				AND rooms.is_hidden = false
+				AND rooms.status = 'published'
+				AND (
+					rooms.search_vector @@ params.tsq
+					OR rooms.title % @query
+					OR rooms.topic % @query
+				)
+				AND (@viewer = 0 OR rooms.host_id NOT IN (
+					SELECT user_id FROM hidden_users WHERE hidden_user_id = @viewer
+				))
+				` + filterSQL + `
+		),
+		ranked AS (
+			SELECT matches.*,
+				0.7 * (text_rank + title_similarity)
+					+ 0.3 * LN(1 + total_listens + 2 * likes_count) / 10 AS score
+			FROM matches
+			ORDER BY ` + orderBy + `
+			LIMIT @limit
+		)
+		SELECT ranked.*,
+			ts_headline('english', ` + HighlightSource("ranked.title") + `, params.tsq, @headline) AS title_highlight,
+			ts_headline('english', ` + HighlightSource("ranked.description") + `, params.tsq, @snippet) AS description_snippet
+		FROM ranked, params
+		ORDER BY ` + orderBy + `
+	`
+
+	params["tsquery"] = BuildPrefixTSQuery(query)
+	params["query"] = query
+	params["viewer"] = currentUserID
+	params["limit"] = limit
+	params["headline"] = SearchHeadlineOptions
+	params["snippet"] = SearchSnippetOptions
+
+	var rooms []roomSearchRow
+	err := db.Raw(sql, params).Scan(&rooms).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	hostIDs := make([]uint, 0, len(rooms))
+	for _, room := range rooms {
+		hostIDs = append(hostIDs, room.HostID)
+	}
+
+	hosts := map[uint]models.User{}
+	if len(hostIDs) > 0 {
+		var hostList []models.User
+		db.Where("id IN ?", hostIDs).Find(&hostList)
+		for _, host := range hostList {
+			hosts[host.ID] = host
+		}
+	}
+
+	results := make([]RoomSearchResult, 0, len(rooms))
+
+	for _, room := range rooms {
+		host := hosts[room.HostID]
+		results = append(results, RoomSearchResult{
+			ID:                 room.ID,
+			Title:              room.Title,
+			Description:        room.Description,
+			Topic:              room.Topic,
+			AudioURL:           room.AudioURL,
+			ThumbnailURL:       room.ThumbnailURL,
+			Duration:           room.Duration,
+			HostID:             room.HostID,
+			HostName:           host.FullName,
+			HostAvatar:         host.ProfilePic,
+			HostFollowersCount: host.FollowersCount,
+			IsLive:             room.IsLive,
+			IsPrivate:          room.IsPrivate,
+			ListenerCount:      room.ListenerCount,
+			TotalListens:       room.TotalListens,
+			LikesCount:         room.LikesCount,
+			CreatedAt:          room.CreatedAt.Format(time.RFC3339),
+			Score:              room.Score,
+			TitleHighlight:     room.TitleHighlight,
+			DescriptionSnippet: room.DescriptionSnippet,
+		})
+	}
+
+	return results, nil
+}
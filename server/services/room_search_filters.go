@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RoomSortRelevance    = "relevance"
+	RoomSortNewest       = "newest"
+	RoomSortMostLiked    = "most_liked"
+	RoomSortMostListened = "most_listened"
+)
+
+const maxFilterTopics = 10
+
+// RoomSearchFilters are the structured filters shared by GlobalSearch and
+// GetQueueFromSearch. Zero values mean "no filter".
+type RoomSearchFilters struct {
+	Topics          []string
+	MinDuration     *int
+	MaxDuration     *int
+	UploadedAfter   *time.Time
+	UploadedBefore  *time.Time
+	VerifiedOnly    bool
+	MinLikes        *int
+	ExcludeListened bool
+	Sort            string
+
+	// PublishedAfter and PublishedBefore bound when rooms went public. They
+	// are not request parameters; saved search alerts use them as their window.
+	PublishedAfter  *time.Time
+	PublishedBefore *time.Time
+}
+
+// ParseRoomSearchFilters reads and validates the filter parameters: topics
+// (comma separated or repeated), min_duration/max_duration in seconds,
+// uploaded_after/uploaded_before as RFC3339 or YYYY-MM-DD, verified_only,
+// min_likes, exclude_listened and sort.
+func ParseRoomSearchFilters(values url.Values) (RoomSearchFilters, error) {
+	filters := RoomSearchFilters{Sort: RoomSortRelevance}
+
+	for _, value := range values["topics"] {
+		for _, topic := range strings.Split(value, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				filters.Topics = append(filters.Topics, topic)
+			}
+		}
+	}
+	if len(filters.Topics) > maxFilterTopics {
+		return filters, fmt.Errorf("at most %d topics can be selected", maxFilterTopics)
+	}
+
+	var err error
+	if filters.MinDuration, err = parseNonNegativeInt(values, "min_duration"); err != nil {
+		return filters, err
+	}
+	if filters.MaxDuration, err = parseNonNegativeInt(values, "max_duration"); err != nil {
+		return filters, err
+	}
+	if filters.MinDuration != nil && filters.MaxDuration != nil && *filters.MinDuration > *filters.MaxDuration {
+		return filters, fmt.Errorf("min_duration cannot be greater than max_duration")
+	}
+
+	if filters.UploadedAfter, err = parseFilterDate(values, "uploaded_after"); err != nil {
+		return filters, err
+	}
+	if filters.UploadedBefore, err = parseFilterDate(values, "uploaded_before"); err != nil {
+		return filters, err
+	}
+	if filters.UploadedAfter != nil && filters.UploadedBefore != nil && filters.UploadedAfter.After(*filters.UploadedBefore) {
+		return filters, fmt.Errorf("uploaded_after cannot be later than uploaded_before")
+	}
+
+	if filters.VerifiedOnly, err = parseFilterBool(values, "verified_only"); err != nil {
+		return filters, err
+	}
+	if filters.MinLikes, err = parseNonNegativeInt(values, "min_likes"); err != nil {
+		return filters, err
+	}
+	if filters.ExcludeListened, err = parseFilterBool(values, "exclude_listened"); err != nil {
+		return filters, err
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		switch sort {
+		case RoomSortRelevance, RoomSortNewest, RoomSortMostLiked, RoomSortMostListened:
+			filters.Sort = sort
+		default:
+			return filters, fmt.Errorf("sort must be one of relevance, newest, most_liked, most_listened")
+		}
+	}
+
+	return filters, nil
+}
+
+// Conditions returns the filters as a SQL condition on the rooms table using
+// named parameters, so it can be used both in raw queries and in Where.
+func (f RoomSearchFilters) Conditions(viewerID uint) (string, map[string]interface{}) {
+	var parts []string
+	params := map[string]interface{}{}
+
+	if len(f.Topics) > 0 {
+		parts = append(parts, "rooms.topic IN @filter_topics")
+		params["filter_topics"] = f.Topics
+	}
+	if f.MinDuration != nil {
+		parts = append(parts, "rooms.duration >= @filter_min_duration")
+		params["filter_min_duration"] = *f.MinDuration
+	}
+	if f.MaxDuration != nil {
+		parts = append(parts, "rooms.duration <= @filter_max_duration")
+		params["filter_max_duration"] = *f.MaxDuration
+	}
+	if f.UploadedAfter != nil {
+		parts = append(parts, "rooms.created_at >= @filter_uploaded_after")
+		params["filter_uploaded_after"] = *f.UploadedAfter
+	}
+	if f.UploadedBefore != nil {
+		parts = append(parts, "rooms.created_at <= @filter_uploaded_before")
+		params["filter_uploaded_before"] = *f.UploadedBefore
+	}
+	if f.PublishedAfter != nil {
+		parts = append(parts, "rooms.published_at > @filter_published_after")
+		params["filter_published_after"] = *f.PublishedAfter
+	}
+	if f.PublishedBefore != nil {
+		parts = append(parts, "rooms.published_at <= @filter_published_before")
+		params["filter_published_before"] = *f.PublishedBefore
+	}
+	if f.VerifiedOnly {
+		parts = append(parts, "rooms.host_id IN (SELECT id FROM users WHERE is_verified = true AND deleted_at IS NULL)")
+	}
+	if f.MinLikes != nil {
+		parts = append(parts, "rooms.likes_count >= @filter_min_likes")
+		params["filter_min_likes"] = *f.MinLikes
+	}
+	if f.ExcludeListened && viewerID > 0 {
+		parts = append(parts, "rooms.id NOT IN (SELECT room_id FROM listen_history WHERE user_id = @filter_viewer AND deleted_at IS NULL)")
+		params["filter_viewer"] = viewerID
+	}
+
+	return strings.Join(parts, " AND "), params
+}
+
+// OrderBy returns the ORDER BY list for the sort mode. relevanceExpr is used
+// for the relevance sort and may be empty when there is nothing to rank on.
+func (f RoomSearchFilters) OrderBy(relevanceExpr string) string {
+	switch f.Sort {
+	case RoomSortNewest:
+		return "created_at DESC, id DESC"
+	case RoomSortMostLiked:
+		return "likes_count DESC, total_listens DESC, id DESC"
+	case RoomSortMostListened:
+		return "total_listens DESC, likes_count DESC, id DESC"
+	}
+	if relevanceExpr == "" {
+		return "likes_count DESC, total_listens DESC, id DESC"
+	}
+	return relevanceExpr + " DESC, total_listens DESC, created_at DESC"
+}
+
+func parseNonNegativeInt(values url.Values, name string) (*int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return &value, nil
+}
+
+func parseFilterDate(values url.Values, name string) (*time.Time, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC3339 timestamp", name)
+	}
+	if name == "uploaded_before" {
+		// A bare date includes the whole day.
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func parseFilterBool(values url.Values, name string) (bool, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"log"
+	"net/url"
+	"time"
+	"voxarena_server/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MaxSavedSearchAlertsPerDay = 5
+	SavedSearchMatchLimit      = 20
+)
+
+// RunSavedSearchAlerts runs every saved search that is due against the rooms
+// published since its last run and notifies the owner about new matches. Users
+// get at most MaxSavedSearchAlertsPerDay alerts; a capped search keeps its
+// window open so the matches roll into the next alert.
+func RunSavedSearchAlerts(db *gorm.DB) error {
+	now := time.Now()
+
+	var searches []models.SavedSearch
+	if err := db.Where("alerts_enabled = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").
+		Find(&searches).Error; err != nil {
+		return err
+	}
+
+	notificationService := NewNotificationService(db)
+
+	for _, search := range searches {
+		sentToday, err := models.CountNotificationsSince(db, search.UserID, models.NotificationTypeSavedSearch, now.Add(-24*time.Hour))
+		if err != nil {
+			log.Printf("Error counting saved search alerts for user %d: %v", search.UserID, err)
+			continue
+		}
+		if sentToday >= MaxSavedSearchAlertsPerDay {
+			db.Model(&search).Update("next_run_at", now.Add(time.Hour))
+			continue
+		}
+
+		filters, err := SavedSearchFilters(search)
+		if err != nil {
+			log.Printf("Skipping saved search %d with invalid filters: %v", search.ID, err)
+			db.Model(&search).Update("alerts_enabled", false)
+			continue
+		}
+
+		since := search.LastRunAt
+		filters.PublishedAfter = &since
+		filters.PublishedBefore = &now
+
+		rooms, err := SearchRooms(db, search.Query, search.UserID, filters, SavedSearchMatchLimit)
+		if err != nil {
+			log.Printf("Error running saved search %d: %v", search.ID, err)
+			continue
+		}
+
+		// The owner's own uploads are not news to them.
+		matches := make([]RoomSearchResult, 0, len(rooms))
+		for _, room := range rooms {
+			if room.HostID != search.UserID {
+				matches = append(matches, room)
+			}
+		}
+
+		if len(matches) > 0 {
+			top := matches[0]
+			topRoom := models.Room{
+				ID:           top.ID,
+				Title:        top.Title,
+				ThumbnailURL: top.ThumbnailURL,
+				HostID:       top.HostID,
+			}
+			if err := notificationService.NotifySavedSearchMatches(&search, len(matches), &topRoom); err != nil {
+				log.Printf("Error notifying saved search %d: %v", search.ID, err)
+				continue
+			}
+		}
+
+		db.Model(&search).Updates(map[string]interface{}{
+			"last_run_at": now,
+			"next_run_at": now.Add(models.SavedSearchFrequencies[search.Frequency]),
+		})
+	}
+
+	return nil
+}
+
+// SavedSearchFilters parses the filters stored with a saved search.
+func SavedSearchFilters(search models.SavedSearch) (RoomSearchFilters, error) {
+	values, err := url.ParseQuery(search.Filters)
+	if err != nil {
+		return RoomSearchFilters{}, err
+	}
+	return ParseRoomSearchFilters(values)
+}
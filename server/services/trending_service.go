@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// TrendingHalfLife is how long it takes an interaction to lose half of
+	// its weight in the trending score.
+	TrendingHalfLife = 24 * time.Hour
+	trendingWindow   = 7 * 24 * time.Hour
+
+	trendingListenWeight   = 1.0
+	trendingListenerWeight = 1.5
+	trendingLikeWeight     = 2.0
+	trendingCommentWeight  = 3.0
+)
+
+type TrendingService struct {
+	db *gorm.DB
+}
+
+func NewTrendingService(db *gorm.DB) *TrendingService {
+	return &TrendingService{db: db}
+}
+
+// Recompute refreshes rooms.trending_score and rooms.weekly_listens from the
+// last week of activity. Every listen, distinct listener, like and comment
+// contributes its weight decayed by age; a listen's weight also scales with
+// how much of the room was heard. Only rows whose values change are written.
+func (ts *TrendingService) Recompute() (int64, error) {
+	result := ts.db.Exec(`
+		WITH listens AS (
+			SELECT room_id, user_id, listened_at,
+				POWER(0.5, EXTRACT(EPOCH FROM (NOW() - listened_at)) / @half_life) AS decay,
+				CASE
+					WHEN is_skipped THEN 0
+					ELSE LEAST(GREATEST(
+						CASE WHEN completion_rate > 1 THEN completion_rate / 100 ELSE completion_rate END,
+					0), 1)
+				END AS completion
+			FROM listen_history
+			WHERE deleted_at IS NULL AND listened_at > @since
+		),
+		listen_scores AS (
+			SELECT room_id,
+				COUNT(*) AS weekly_listens,
+				SUM(decay * (0.5 + 0.5 * completion)) AS score
+			FROM listens
+			GROUP BY room_id
+		),
+		listener_scores AS (
+			SELECT room_id, SUM(decay) AS score
+			FROM (
+				SELECT room_id, user_id, MAX(decay) AS decay
+				FROM listens
+				GROUP BY room_id, user_id
+			) latest
+			GROUP BY room_id
+		),
+		like_scores AS (
+			SELECT room_id, SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - created_at)) / @half_life)) AS score
+			FROM room_likes
+			WHERE created_at > @since
+			GROUP BY room_id
+		),
+		comment_scores AS (
+			SELECT room_id, SUM(POWER(0.5, EXTRACT(EPOCH FROM (NOW() - created_at)) / @half_life)) AS score
+			FROM comments
+			WHERE deleted_at IS NULL AND created_at > @since
+			GROUP BY room_id
+		),
+		scores AS (
+			SELECT r.id AS room_id,
+				COALESCE(ls.weekly_listens, 0) AS weekly_listens,
+				@listen_weight * COALESCE(ls.score, 0)
+					+ @listener_weight * COALESCE(lr.score, 0)
+					+ @like_weight * COALESCE(lk.score, 0)
+					+ @comment_weight * COALESCE(cm.score, 0) AS trending_score
+			FROM rooms r
+			LEFT JOIN listen_scores ls ON ls.room_id = r.id
+			LEFT JOIN listener_scores lr ON lr.room_id = r.id
+			LEFT JOIN like_scores lk ON lk.room_id = r.id
+			LEFT JOIN comment_scores cm ON cm.room_id = r.id
+			WHERE r.deleted_at IS NULL
+		)
+		UPDATE rooms
+		SET trending_score = scores.trending_score,
+			weekly_listens = scores.weekly_listens
+		FROM scores
+		WHERE rooms.id = scores.room_id
+			AND (rooms.trending_score <> scores.trending_score OR rooms.weekly_listens <> scores.weekly_listens)
+	`, map[string]interface{}{
+		"half_life":       TrendingHalfLife.Seconds(),
+		"since":           time.Now().Add(-trendingWindow),
+		"listen_weight":   trendingListenWeight,
+		"listener_weight": trendingListenerWeight,
+		"like_weight":     trendingLikeWeight,
+		"comment_weight":  trendingCommentWeight,
+	})
+
+	return result.RowsAffected, result.Error
+}